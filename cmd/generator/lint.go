@@ -0,0 +1,28 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/blksails/pkg-index/generator"
+)
+
+// runLint implements the lint subcommand, which checks the go-import tags of
+// previously generated pages.
+func runLint(args []string) {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	dir := fs.String("dir", "public", "directory containing the generated site")
+	fs.Parse(args)
+
+	problems, err := generator.LintSite(*dir)
+	if err != nil {
+		log.Fatalf("Error linting %s: %v", *dir, err)
+	}
+	for _, p := range problems {
+		log.Printf("%s: %s", p.File, p.Message)
+	}
+	if len(problems) > 0 {
+		log.Fatalf("Found %d problem(s)", len(problems))
+	}
+	log.Printf("✓ No problems found")
+}
@@ -2,315 +2,128 @@ package main
 
 import (
 	"context"
-	"fmt"
-	"html/template"
+	"flag"
 	"log"
+	"log/slog"
 	"os"
-	"path/filepath"
 	"strings"
+	"time"
 
-	"github.com/google/go-github/v45/github"
-	"golang.org/x/oauth2"
+	"github.com/blksails/pkg-index/generator"
 )
 
 const (
-	orgName     = "blksails"
-	baseDomain  = "pkg.blksails.net"
-	basePackage = "pkg.blksails.net"
+	orgName    = "blksails"
+	baseDomain = "pkg.blksails.net"
 )
 
-type PackageInfo struct {
-	ImportPath     string // module path from go.mod
-	RepoImportPath string // VCS root import path (for go-import prefix)
-	RepoURL        string
-	Description    string
+// flags holds the command line flags shared by all subcommands.
+type flags struct {
+	cfg      generator.Config
+	orgs     string
+	logLevel slog.Level
+	logFile  string
 }
 
-func main() {
-	ctx := context.Background()
-
-	log.Printf("GITHUB_TOKEN: %s", os.Getenv("GITHUB_TOKEN"))
-	// 使用 GitHub token 创建客户端
-	token := os.Getenv("GITHUB_TOKEN")
-	if token == "" {
-		log.Fatal("GITHUB_TOKEN environment variable is required")
-	}
-
-	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
-	tc := oauth2.NewClient(ctx, ts)
-	client := github.NewClient(tc)
+func (f *flags) register(fs *flag.FlagSet) {
+	fs.TextVar(&f.logLevel, "log-level", slog.LevelInfo, "minimum log level: debug, info, warn or error; debug logs every API call")
+	fs.StringVar(&f.logFile, "log-file", "", "also write JSON logs to this file")
+	fs.StringVar(&f.orgs, "orgs", orgName, "comma separated organizations whose repositories are indexed")
+	fs.StringVar(&f.cfg.User, "user", "", "index the repositories of this user account instead of --orgs")
+	fs.StringVar(&f.cfg.BaseDomain, "base-domain", baseDomain, "vanity import domain of the indexed modules")
+	fs.StringVar(&f.cfg.OutputDir, "output-dir", "public", "directory the site is written to")
+	fs.StringVar(&f.cfg.VCSProvider, "vcs-provider", "github", "hosting provider of the organization: github (token from GITHUB_TOKEN) or gitlab (token from GITLAB_TOKEN)")
+	fs.StringVar(&f.cfg.BaseBranch, "base-branch", "", "branch to scan instead of each repository's default branch")
+	fs.BoolVar(&f.cfg.IncludeNonModule, "include-non-module", false, "also index Go repositories without a go.mod under a path derived from the repository name")
+	fs.BoolVar(&f.cfg.IncludeInternal, "include-internal", false, "list repositories named internal-* or tagged with the internal topic on the index page")
+	fs.BoolVar(&f.cfg.NoSubpackages, "no-subpackages", false, "index modules without their subpackages")
+	fs.StringVar(&f.cfg.SubpackageRegex, "subpackage-regex", "", "only index subpackages whose path relative to the module root matches, e.g. ^api/")
+	fs.IntVar(&f.cfg.MaxRepos, "max-repos", 0, "stop after indexing this many repositories (0 means no limit)")
+	fs.IntVar(&f.cfg.RateLimitBuffer, "rate-limit-buffer", 0, "stop with an error once fewer API calls than this remain (0 disables the check)")
+	fs.StringVar(&f.cfg.AfterDate, "after-date", "", "only process repositories pushed to on or after this date (YYYY-MM-DD); older ones are reused from --state-file")
+	fs.StringVar(&f.cfg.TagPrefix, "tag-prefix", "", "only consider tags with this prefix as versions, e.g. release- for release-1.2.3")
+	fs.StringVar(&f.cfg.MirrorsFile, "mirrors-file", "", "YAML file mapping import paths to mirror repository URLs keyed by provider")
+	fs.StringVar(&f.cfg.StateFile, "state-file", "", "state file (e.g. .pkgindex-state.json) used to skip repositories not pushed to since the last run")
+	fs.BoolVar(&f.cfg.Keychain, "keychain", false, "read the GitHub token from the OS keychain before falling back to GITHUB_TOKEN")
+	fs.Int64Var(&f.cfg.GitHubAppID, "github-app-id", 0, "authenticate as this GitHub App instead of with GITHUB_TOKEN")
+	fs.Int64Var(&f.cfg.GitHubAppInstallationID, "github-app-installation-id", 0, "installation of --github-app-id in the indexed organization")
+	fs.StringVar(&f.cfg.GitHubAppPrivateKey, "github-app-private-key", "", "PEM file with the private key of --github-app-id")
+	fs.DurationVar(&f.cfg.GitHubAppJWTTTL, "github-app-jwt-ttl", generator.DefaultGitHubAppJWTTTL, "lifetime of the GitHub App JWT (at most 10m); installation tokens are refreshed before they expire")
+	fs.BoolVar(&f.cfg.GitHubGraphQL, "github-graphql", false, "fetch repository metadata and go.mod files with one GraphQL query per 100 repositories")
+	fs.IntVar(&f.cfg.GitHubPerPage, "github-per-page", 100, "results per GitHub list request (at most 100)")
+	fs.IntVar(&f.cfg.HTTPRetries, "http-retries", 0, "retry API requests failing with a network error or a 5xx status this many times")
+	fs.DurationVar(&f.cfg.HTTPRetryBackoff, "http-retry-backoff", 500*time.Millisecond, "delay before the first --http-retries retry, doubled after each one")
+	fs.BoolVar(&f.cfg.TLSSkipVerify, "tls-skip-verify", false, "do not verify TLS certificates of API calls (insecure; for HTTPS inspection proxies)")
+	fs.StringVar(&f.cfg.APIBaseURL, "api-base-url", "", "base URL of the provider API, e.g. a caching proxy such as https://ghproxy.example.com/api/v3")
+}
 
-	// 获取组织下的所有仓库（分页）
-	log.Printf("Fetching repositories for organization: %s", orgName)
-	var repos []*github.Repository
-	opt := &github.RepositoryListByOrgOptions{
-		ListOptions: github.ListOptions{PerPage: 100},
+// config applies the logging flags and returns the validated configuration,
+// exiting on invalid flags before any API call is made.
+func (f *flags) config() *generator.Config {
+	if err := setupLogging(f.logLevel, f.logFile); err != nil {
+		log.Fatalf("Error opening log file: %v", err)
 	}
-	for {
-		page, resp, err := client.Repositories.ListByOrg(ctx, orgName, opt)
-		if err != nil {
-			log.Fatalf("Error listing repositories: %v", err)
-		}
-		repos = append(repos, page...)
-		if resp.NextPage == 0 {
-			break
+	f.cfg.Orgs = nil
+	for _, org := range strings.Split(f.orgs, ",") {
+		if org = strings.TrimSpace(org); org != "" {
+			f.cfg.Orgs = append(f.cfg.Orgs, org)
 		}
-		opt.Page = resp.NextPage
 	}
-	log.Printf("Found %d repositories", len(repos))
-
-	var packages []PackageInfo
-
-	for _, repo := range repos {
-		log.Printf("Processing repository: %s", repo.GetName())
-		if repo.GetLanguage() == "Go" {
-			log.Printf("  Found Go repository: %s", repo.GetName())
-		}
-
-		// Get repository root contents
-		_, contents, _, err := client.Repositories.GetContents(ctx, orgName, repo.GetName(), "", nil)
-		if err != nil {
-			log.Printf("Error getting contents for %s: %v", repo.GetName(), err)
-			continue
-		}
-
-		// Check root go.mod
-		log.Printf("  Checking root go.mod for %s", repo.GetName())
-		if modContent, _, _, err := client.Repositories.GetContents(ctx, orgName, repo.GetName(), "go.mod", nil); err == nil {
-			if fileContent, err := modContent.GetContent(); err == nil {
-				moduleName := parseModuleName(fileContent)
-				log.Printf("  Root module: %s", moduleName)
-			if strings.HasPrefix(moduleName, basePackage) {
-				repoImportPath := moduleName
-				pkgInfo := PackageInfo{
-						ImportPath:     moduleName,
-						RepoImportPath: repoImportPath,
-						RepoURL:        repo.GetHTMLURL(),
-						Description:    repo.GetDescription(),
-					}
-					packages = append(packages, pkgInfo)
-					if err := generateHTML(pkgInfo); err != nil {
-						log.Printf("  Error generating HTML for %s: %v", moduleName, err)
-					} else {
-						log.Printf("  ✓ Generated HTML for %s", moduleName)
-					}
-
-					subPkgCount := 0
-					for _, content := range contents {
-						if content.GetType() == "file" && strings.HasSuffix(content.GetName(), ".go") {
-							dir := filepath.Dir(content.GetPath())
-							if dir == "." {
-								continue
-							}
-							subPkgInfo := PackageInfo{
-								ImportPath:     filepath.Join(moduleName, dir),
-								RepoImportPath: repoImportPath,
-								RepoURL:        repo.GetHTMLURL(),
-								Description:    repo.GetDescription(),
-							}
-							if err := generateHTML(subPkgInfo); err != nil {
-								log.Printf("  Error generating HTML for %s: %v", subPkgInfo.ImportPath, err)
-							} else {
-								log.Printf("  ✓ Generated HTML for subpackage: %s", subPkgInfo.ImportPath)
-								subPkgCount++
-							}
-						}
-					}
-					if subPkgCount > 0 {
-						log.Printf("  Generated %d subpackage(s) for %s", subPkgCount, repo.GetName())
-					}
-				} else {
-					log.Printf("  Skipping root module: doesn't start with %s", basePackage)
-				}
-			} else {
-				log.Printf("  Failed to read root go.mod: %v", err)
-			}
-		} else {
-			log.Printf("  No root go.mod found for %s", repo.GetName())
-		}
-
-		// Check first-level subdirectories for go.mod (sub-modules)
-		generatedRoots := make(map[string]bool)
-		for _, content := range contents {
-			if content.GetType() != "dir" {
-				continue
-			}
-			subDir := content.GetName()
-			subModContent, _, _, err := client.Repositories.GetContents(ctx, orgName, repo.GetName(), subDir+"/go.mod", nil)
-			if err != nil {
-				continue
-			}
-			fileContent, err := subModContent.GetContent()
-			if err != nil {
-				log.Printf("  Failed to read %s/go.mod: %v", subDir, err)
-				continue
-			}
-			moduleName := parseModuleName(fileContent)
-			log.Printf("  Sub-module found: %s (in %s/)", moduleName, subDir)
-			if !strings.HasPrefix(moduleName, basePackage) {
-				log.Printf("  Skipping sub-module %s: doesn't start with %s", moduleName, basePackage)
-				continue
-			}
-
-			repoImportPath := strings.TrimSuffix(moduleName, "/"+subDir)
-
-			// Ensure repo root HTML exists for go-import verification
-			if !generatedRoots[repoImportPath] {
-				rootPkg := PackageInfo{
-					ImportPath:     repoImportPath,
-					RepoImportPath: repoImportPath,
-					RepoURL:        repo.GetHTMLURL(),
-					Description:    repo.GetDescription(),
-				}
-				if err := generateHTML(rootPkg); err != nil {
-					log.Printf("  Error generating repo root HTML for %s: %v", repoImportPath, err)
-				} else {
-					log.Printf("  ✓ Generated repo root HTML for %s", repoImportPath)
-					generatedRoots[repoImportPath] = true
-				}
-			}
-
-			pkgInfo := PackageInfo{
-				ImportPath:     moduleName,
-				RepoImportPath: repoImportPath,
-				RepoURL:        repo.GetHTMLURL(),
-				Description:    repo.GetDescription(),
-			}
-			packages = append(packages, pkgInfo)
-			if err := generateHTML(pkgInfo); err != nil {
-				log.Printf("  Error generating HTML for sub-module %s: %v", moduleName, err)
-			} else {
-				log.Printf("  ✓ Generated HTML for sub-module: %s", moduleName)
-			}
-		}
-	}
-
-	// 生成主页
-	log.Printf("\nGenerating index HTML with %d package(s)", len(packages))
-	if err := generateIndexHTML(packages); err != nil {
-		log.Printf("Error generating index HTML: %v", err)
-	} else {
-		log.Printf("✓ Successfully generated index HTML")
+	if err := f.cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration:\n%v", err)
 	}
-
-	log.Printf("\n=== Generation Complete ===")
-	log.Printf("Total packages processed: %d", len(packages))
-	log.Printf("Index page: public/index.html")
+	return &f.cfg
 }
 
-func parseModuleName(content string) string {
-	lines := strings.Split(content, "\n")
-	for _, line := range lines {
-		if strings.HasPrefix(strings.TrimSpace(line), "module ") {
-			return strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "module "))
+func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "report":
+			runReport(os.Args[2:])
+			return
+		case "lint":
+			runLint(os.Args[2:])
+			return
 		}
 	}
-	return ""
-}
-
-func generateHTML(pkg PackageInfo) error {
-	tmpl := template.Must(template.New("index").Parse(`<!DOCTYPE html>
-<html>
-<head>
-    <meta charset="utf-8">
-    <meta name="go-import" content="{{ .RepoImportPath }} git {{ .RepoURL }}">
-    <meta name="go-source" content="{{ .RepoImportPath }} {{ .RepoURL }} {{ .RepoURL }}/tree/master{/dir} {{ .RepoURL }}/blob/master{/dir}/{file}#L{line}">
-    <meta http-equiv="refresh" content="0; url={{ .RepoURL }}">
-</head>
-<body>
-    Redirecting to <a href="{{ .RepoURL }}">{{ .RepoURL }}</a>...
-</body>
-</html>`))
-
-	// 创建目录结构
-	relPath := strings.TrimPrefix(pkg.ImportPath, baseDomain+"/")
-	dirPath := filepath.Join("public", relPath)
-	if err := os.MkdirAll(dirPath, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %v", err)
-	}
-
-	// 创建 index.html 文件
-	f, err := os.Create(filepath.Join(dirPath, "index.html"))
-	if err != nil {
-		return fmt.Errorf("failed to create file: %v", err)
-	}
-	defer f.Close()
-
-	if err := tmpl.Execute(f, pkg); err != nil {
-		return fmt.Errorf("failed to execute template: %v", err)
-	}
-
-	return nil
+	runGenerate(os.Args[1:])
 }
 
-func generateIndexHTML(packages []PackageInfo) error {
-	tmpl := template.Must(template.New("main-index").Parse(`<!DOCTYPE html>
-<html>
-<head>
-    <meta charset="utf-8">
-    <title>pkg.blksails.net</title>
-    <style>
-        body {
-            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, Oxygen, Ubuntu, Cantarell, 'Open Sans', 'Helvetica Neue', sans-serif;
-            max-width: 800px;
-            margin: 0 auto;
-            padding: 2rem;
-            line-height: 1.6;
-        }
-        .package-list {
-            margin-top: 2rem;
-        }
-        .package-item {
-            margin-bottom: 1.5rem;
-            padding: 1rem;
-            border: 1px solid #eee;
-            border-radius: 4px;
-        }
-        .package-item h3 {
-            margin: 0 0 0.5rem 0;
-        }
-        .package-item p {
-            margin: 0.5rem 0;
-            color: #666;
-        }
-        code {
-            background: #f5f5f5;
-            padding: 0.2rem 0.4rem;
-            border-radius: 3px;
-            font-size: 0.9em;
-        }
-    </style>
-</head>
-<body>
-    <h1>pkg.blksails.net</h1>
-    <p>This is the package index for blksails Go packages.</p>
-    <p>To use these packages in your Go project, simply import them using the <code>pkg.blksails.net/...</code>
-        import path.</p>
-    
-    <div class="package-list">
-        <h2>Available Packages</h2>
-        {{range .}}
-        <div class="package-item">
-            <h3><a href="{{.RepoURL}}">{{.ImportPath}}</a></h3>
-            {{if .Description}}
-            <p>{{.Description}}</p>
-            {{end}}
-            <p><code>go get {{.ImportPath}}</code></p>
-        </div>
-        {{end}}
-    </div>
-</body>
-</html>`))
+func runGenerate(args []string) {
+	var f flags
+	fs := flag.NewFlagSet("generator", flag.ExitOnError)
+	f.register(fs)
+	fs.BoolVar(&f.cfg.HTMLMinify, "html-minify", false, "strip whitespace from the generated HTML")
+	fs.BoolVar(&f.cfg.RedactDescriptions, "redact-descriptions", false, "leave repository descriptions out of the generated site")
+	fs.BoolVar(&f.cfg.CleanBefore, "clean-before", false, "delete the output directory before writing, removing pages of deleted packages")
+	fs.BoolVar(&f.cfg.OGImages, "generate-og-images", false, "write an og-image.svg social preview next to every package page")
+	fs.BoolVar(&f.cfg.NoHTMLRefresh, "no-html-refresh", false, "omit the meta refresh redirect of package pages, which some security scanners flag, and redirect with JavaScript instead")
+	fs.BoolVar(&f.cfg.ContentHash, "content-hash", false, "write a SHA-256 of all generated files to content-hash.txt")
+	fs.StringVar(&f.cfg.ExtraCSSFile, "extra-css-file", "", "CSS file embedded after the default styles of every page")
+	fs.StringVar(&f.cfg.PregenerateHook, "pregenerate-hook", "", "shell command run before generation, e.g. \"npm run build\"")
+	fs.StringVar(&f.cfg.PostgenerateHook, "postgenerate-hook", "", "shell command run after the site is written and before it is uploaded")
+	fs.StringVar(&f.cfg.GCSBucket, "gcs-bucket", "", "upload the generated site to this Google Cloud Storage bucket using Application Default Credentials")
+	fs.StringVar(&f.cfg.GCSPrefix, "gcs-prefix", "", "object name prefix for --gcs-bucket uploads")
+	fs.StringVar(&f.cfg.AnnounceWebhook, "announce-webhook", "", "POST a JSON notification to this URL (e.g. a Slack incoming webhook) after a successful run")
+	fs.StringVar(&f.cfg.AnnounceTemplate, "announce-template", "", "Go text/template file rendering the run stats into the --announce-webhook body")
+	fs.IntVar(&f.cfg.ParallelIO, "parallel-io", 1, "number of goroutines processing and writing packages")
+	fs.StringVar(&f.cfg.ParallelismStrategy, "parallelism-strategy", generator.StrategyRoundRobin, "scheduling of the --parallel-io writers: round-robin or work-stealing")
+	fs.Parse(args)
+	cfg := f.config()
 
-	f, err := os.Create("public/index.html")
+	ctx := context.Background()
+	stats, err := generator.Run(ctx, cfg)
 	if err != nil {
-		return fmt.Errorf("failed to create index file: %v", err)
+		log.Fatal(err)
 	}
-	defer f.Close()
-
-	if err := tmpl.Execute(f, packages); err != nil {
-		return fmt.Errorf("failed to execute template: %v", err)
+	slog.Info("generation stats",
+		"repos_fetched", stats.ReposFetched,
+		"repos_skipped", stats.ReposSkipped,
+		"repos_failed", stats.ReposFailed,
+		"html_files", stats.HTMLFiles,
+		"bytes_written", stats.BytesWritten,
+		"duration", stats.Duration)
+	if err := generator.Announce(ctx, cfg, stats); err != nil {
+		log.Printf("Failed to send announcement: %v", err)
 	}
-
-	return nil
 }
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"log"
+	"os"
+
+	"github.com/blksails/pkg-index/generator"
+)
+
+// runReport implements the report subcommand, which writes the metadata of
+// every indexed package as CSV.
+func runReport(args []string) {
+	var f flags
+	fs := flag.NewFlagSet("report", flag.ExitOnError)
+	f.register(fs)
+	output := fs.String("output", "", "write the CSV to this file instead of stdout")
+	fs.Parse(args)
+	cfg := f.config()
+
+	packages, err := generator.Collect(context.Background(), cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	w := io.Writer(os.Stdout)
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			log.Fatalf("Error creating report file: %v", err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := generator.WriteReport(w, packages); err != nil {
+		log.Fatalf("Error writing report: %v", err)
+	}
+	log.Printf("✓ Wrote report with %d package(s)", len(packages))
+}
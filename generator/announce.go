@@ -0,0 +1,53 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"text/template"
+)
+
+// defaultAnnounceTemplate is a Slack incoming webhook payload.
+const defaultAnnounceTemplate = `{"text": "pkg-index: wrote {{.HTMLFiles}} pages from {{.ReposFetched}} repositories ({{.ReposFailed}} failed) in {{.Duration.Round 1000000000}}"}`
+
+// Announce posts the stats of a finished run to cfg.AnnounceWebhook, rendered
+// with cfg.AnnounceTemplate or a Slack compatible default. It does nothing
+// when no webhook is configured.
+func Announce(ctx context.Context, cfg *Config, stats Stats) error {
+	if cfg.AnnounceWebhook == "" {
+		return nil
+	}
+	text := defaultAnnounceTemplate
+	if cfg.AnnounceTemplate != "" {
+		b, err := os.ReadFile(cfg.AnnounceTemplate)
+		if err != nil {
+			return fmt.Errorf("failed to read announce template: %v", err)
+		}
+		text = string(b)
+	}
+	tmpl, err := template.New("announce").Parse(text)
+	if err != nil {
+		return fmt.Errorf("failed to parse announce template: %v", err)
+	}
+	var body bytes.Buffer
+	if err := tmpl.Execute(&body, stats); err != nil {
+		return fmt.Errorf("failed to execute announce template: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.AnnounceWebhook, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
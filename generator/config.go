@@ -0,0 +1,218 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Config controls a generator run.
+type Config struct {
+	Orgs       []string // organizations whose repositories are indexed
+	User       string   // index this user's repositories instead of Orgs
+	BaseDomain string   // vanity import domain, e.g. pkg.blksails.net
+	OutputDir  string   // directory the site is written to
+
+	VCSProvider   string // github or gitlab
+	APIBaseURL    string // overrides the provider API endpoint, e.g. for a caching proxy
+	Keychain      bool   // read the GitHub token from the OS keychain
+	TLSSkipVerify bool   // skip certificate checks of API calls, for HTTPS inspection proxies
+	GitHubGraphQL bool   // list GitHub repositories and their go.mod with GraphQL
+	GitHubPerPage int    // page size of GitHub list requests, 1 to 100; 0 means 100
+
+	HTTPRetries      int           // retries of API requests failing with a network error or 5xx
+	HTTPRetryBackoff time.Duration // delay before the first retry, doubled after each one
+
+	// GitHub App authentication, used instead of a token when GitHubAppID
+	// is set.
+	GitHubAppID             int64
+	GitHubAppInstallationID int64
+	GitHubAppPrivateKey     string        // PEM file
+	GitHubAppJWTTTL         time.Duration // lifetime of the app JWT; 0 means DefaultGitHubAppJWTTTL
+
+	BaseBranch       string // branch scanned instead of each repository's default branch
+	IncludeNonModule bool   // index Go repositories without a go.mod
+	IncludeInternal  bool   // list internal repositories on the index page
+	NoSubpackages    bool   // index modules without their subpackages
+	SubpackageRegex  string // only index subpackages whose module-relative path matches
+	StateFile        string // enables incremental runs when set
+	MaxRepos         int    // 0 means no limit
+	RateLimitBuffer  int    // stop when fewer API calls remain; 0 disables the check
+	AfterDate        string // YYYY-MM-DD; reuse the StateFile packages of repositories not pushed to since then
+	TagPrefix        string // only tags with this prefix are versions, e.g. "release-"
+	MirrorsFile      string // YAML file listing mirror repositories per import path
+
+	HTMLMinify         bool
+	RedactDescriptions bool   // omit repository descriptions from every output
+	CleanBefore        bool   // delete OutputDir before writing the site
+	OGImages           bool   // write an SVG social preview image for every package
+	NoHTMLRefresh      bool   // leave the meta refresh redirect out of package pages
+	ContentHash        bool   // write a SHA-256 of the whole site to content-hash.txt
+	ExtraCSSFile       string // CSS appended after the default styles of every page
+	GCSBucket          string // also upload the site to this Google Cloud Storage bucket
+	GCSPrefix          string // object name prefix within GCSBucket
+
+	ParallelIO          int    // goroutines running the package pipeline; 0 means 1
+	ParallelismStrategy string // StrategyRoundRobin (default) or StrategyWorkStealing
+
+	PregenerateHook  string // shell command run before the site is generated
+	PostgenerateHook string // shell command run after the site is written
+
+	AnnounceWebhook  string // URL receiving a JSON notification after a successful run
+	AnnounceTemplate string // text/template file rendering Stats into the notification body
+
+	Middleware []Middleware     // set with WithMiddleware
+	Stages     []StageInsertion // set with WithStage
+}
+
+// Validate reports every invalid field of c.
+func (c *Config) Validate() error {
+	var errs []error
+	if len(c.Orgs) == 0 && c.User == "" {
+		errs = append(errs, errors.New("at least one organization or a user is required"))
+	}
+	for _, org := range c.Orgs {
+		if strings.TrimSpace(org) == "" {
+			errs = append(errs, errors.New("organization names must not be empty"))
+			break
+		}
+	}
+	if err := validateDomain(c.BaseDomain); err != nil {
+		errs = append(errs, err)
+	}
+	if err := validateOutputDir(c.OutputDir); err != nil {
+		errs = append(errs, err)
+	}
+	switch c.VCSProvider {
+	case "github", "gitlab":
+	case "bitbucket":
+		errs = append(errs, fmt.Errorf("vcs provider %q is not supported yet", c.VCSProvider))
+	default:
+		errs = append(errs, fmt.Errorf("unknown vcs provider %q", c.VCSProvider))
+	}
+	if c.APIBaseURL != "" {
+		if u, err := url.Parse(c.APIBaseURL); err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, fmt.Errorf("invalid API base URL %q", c.APIBaseURL))
+		}
+	}
+	if c.GitHubAppID != 0 {
+		if c.GitHubAppInstallationID == 0 || c.GitHubAppPrivateKey == "" {
+			errs = append(errs, errors.New("GitHub App authentication requires an installation ID and a private key"))
+		}
+		if c.GitHubAppJWTTTL < 0 || c.GitHubAppJWTTTL > 10*time.Minute {
+			errs = append(errs, fmt.Errorf("GitHub App JWT TTL must be between 0 and 10m, got %s", c.GitHubAppJWTTTL))
+		}
+	}
+	if c.GitHubPerPage < 0 || c.GitHubPerPage > 100 {
+		errs = append(errs, fmt.Errorf("GitHub page size must be between 1 and 100, got %d", c.GitHubPerPage))
+	}
+	if c.HTTPRetries < 0 {
+		errs = append(errs, fmt.Errorf("http retries must not be negative, got %d", c.HTTPRetries))
+	}
+	if c.HTTPRetryBackoff < 0 {
+		errs = append(errs, fmt.Errorf("http retry backoff must not be negative, got %s", c.HTTPRetryBackoff))
+	}
+	if c.MaxRepos < 0 {
+		errs = append(errs, fmt.Errorf("max repos must not be negative, got %d", c.MaxRepos))
+	}
+	if c.RateLimitBuffer < 0 {
+		errs = append(errs, fmt.Errorf("rate limit buffer must not be negative, got %d", c.RateLimitBuffer))
+	}
+	if _, err := regexp.Compile(c.SubpackageRegex); err != nil {
+		errs = append(errs, fmt.Errorf("invalid subpackage regex: %v", err))
+	}
+	if _, err := c.afterDate(); err != nil {
+		errs = append(errs, fmt.Errorf("invalid after date %q, expected YYYY-MM-DD", c.AfterDate))
+	}
+	if c.AfterDate != "" && c.StateFile == "" {
+		errs = append(errs, errors.New("after date requires a state file to keep the repositories pushed before it"))
+	}
+	if c.AnnounceWebhook != "" {
+		if u, err := url.Parse(c.AnnounceWebhook); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("invalid announce webhook URL %q", c.AnnounceWebhook))
+		}
+	}
+	if c.AnnounceTemplate != "" && c.AnnounceWebhook == "" {
+		errs = append(errs, errors.New("announce template requires an announce webhook"))
+	}
+	if c.CleanBefore {
+		if abs, err := filepath.Abs(c.OutputDir); err == nil {
+			if wd, err := os.Getwd(); err == nil && (abs == filepath.Dir(abs) || abs == wd) {
+				errs = append(errs, fmt.Errorf("refusing to clean output directory %q", c.OutputDir))
+			}
+		}
+	}
+	if c.GCSPrefix != "" && c.GCSBucket == "" {
+		errs = append(errs, errors.New("gcs prefix requires a gcs bucket"))
+	}
+	switch c.ParallelismStrategy {
+	case "", StrategyRoundRobin, StrategyWorkStealing:
+	default:
+		errs = append(errs, fmt.Errorf("unknown parallelism strategy %q", c.ParallelismStrategy))
+	}
+	if c.ParallelIO < 0 {
+		errs = append(errs, fmt.Errorf("parallel io must not be negative, got %d", c.ParallelIO))
+	}
+	return errors.Join(errs...)
+}
+
+func validateDomain(domain string) error {
+	if domain == "" {
+		return errors.New("base domain is required")
+	}
+	if strings.Contains(domain, "://") || strings.ContainsAny(domain, "/ ") {
+		return fmt.Errorf("base domain %q must be a bare host name", domain)
+	}
+	if u, err := url.Parse("https://" + domain); err != nil || u.Host != domain {
+		return fmt.Errorf("invalid base domain %q", domain)
+	}
+	return nil
+}
+
+// validateOutputDir checks that dir is a directory or can be created.
+func validateOutputDir(dir string) error {
+	if dir == "" {
+		return errors.New("output directory is required")
+	}
+	info, err := os.Stat(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		parent, err := os.Stat(filepath.Dir(dir))
+		if err != nil || !parent.IsDir() {
+			return fmt.Errorf("output directory %q cannot be created: parent directory is missing", dir)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("output directory %q is not reachable: %v", dir, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("output directory %q is not a directory", dir)
+	}
+	return nil
+}
+
+// includeSubpackage reports whether the subpackage in dir, relative to its
+// module root, is indexed.
+func (c *Config) includeSubpackage(dir string) bool {
+	if c.NoSubpackages {
+		return false
+	}
+	if c.SubpackageRegex == "" {
+		return true
+	}
+	re, err := regexp.Compile(c.SubpackageRegex)
+	return err == nil && re.MatchString(dir)
+}
+
+// afterDate parses AfterDate, returning the zero time when it is unset.
+func (c *Config) afterDate() (time.Time, error) {
+	if c.AfterDate == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.DateOnly, c.AfterDate)
+}
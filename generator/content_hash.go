@@ -0,0 +1,41 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const contentHashName = "content-hash.txt"
+
+// writeContentHash writes a SHA-256 of every file below dir, in path order,
+// to content-hash.txt so deploy pipelines can tell whether the site changed.
+// health.json is skipped, as its timestamp changes on every run.
+func writeContentHash(dir string) (string, error) {
+	h := sha256.New()
+	err := filepath.WalkDir(dir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, name)
+		if err != nil || rel == contentHashName || rel == healthCheckName {
+			return err
+		}
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		io.WriteString(h, filepath.ToSlash(rel)+"\x00")
+		_, err = io.Copy(h, f)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+	return sum, writeFileAtomic(filepath.Join(dir, contentHashName), []byte(sum+"\n"))
+}
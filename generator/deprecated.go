@@ -0,0 +1,39 @@
+package generator
+
+import (
+	"slices"
+	"strings"
+)
+
+// deprecatedModules maps modules and packages that were officially
+// superseded by the standard library or an official successor to their
+// replacement.
+var deprecatedModules = map[string]string{
+	"golang.org/x/xerrors":             "errors",
+	"golang.org/x/net/context":         "context",
+	"golang.org/x/exp/slices":          "slices",
+	"golang.org/x/exp/maps":            "maps",
+	"golang.org/x/exp/slog":            "log/slog",
+	"golang.org/x/crypto/ssh/terminal": "golang.org/x/term",
+	"github.com/golang/protobuf":       "google.golang.org/protobuf",
+	"github.com/golang/mock":           "go.uber.org/mock",
+}
+
+// deprecatedBy returns the replacement of a deprecated module, or "".
+func deprecatedBy(importPath string) string {
+	return deprecatedModules[importPath]
+}
+
+// deprecatedDeps adds to found the deprecatedModules entries that paths, module
+// requirements or import paths, refer to, and returns them sorted.
+func deprecatedDeps(found []string, paths []string) []string {
+	for _, p := range paths {
+		for module := range deprecatedModules {
+			if (p == module || strings.HasPrefix(p, module+"/")) && !slices.Contains(found, module) {
+				found = append(found, module)
+			}
+		}
+	}
+	slices.Sort(found)
+	return found
+}
@@ -0,0 +1,95 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	gcsUploadURL = "https://storage.googleapis.com/upload/storage/v1/b/"
+	gcsScope     = "https://www.googleapis.com/auth/devstorage.read_write"
+)
+
+// siteWriter stores a file of the generated site under its slash-separated
+// path relative to the site root.
+type siteWriter interface {
+	WriteFile(ctx context.Context, name string, data []byte) error
+}
+
+// gcsWriter uploads files to a Google Cloud Storage bucket through the JSON
+// API, authenticated with Application Default Credentials.
+type gcsWriter struct {
+	httpClient *http.Client
+	bucket     string
+	prefix     string // object name prefix, e.g. "site/"
+}
+
+func newGCSWriter(ctx context.Context, bucket, prefix string) (*gcsWriter, error) {
+	client, err := google.DefaultClient(ctx, gcsScope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find Google Cloud credentials: %v", err)
+	}
+	client.Transport = &loggingTransport{base: client.Transport}
+	return &gcsWriter{httpClient: client, bucket: bucket, prefix: prefix}, nil
+}
+
+func (w *gcsWriter) WriteFile(ctx context.Context, name string, data []byte) error {
+	query := url.Values{"uploadType": {"media"}, "name": {path.Join(w.prefix, name)}}
+	endpoint := gcsUploadURL + url.PathEscape(w.bucket) + "/o?" + query.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("gcs upload of %s: %s: %s", name, resp.Status, bytes.TrimSpace(body))
+	}
+	return nil
+}
+
+// uploadSite copies every file below dir to w and returns how many were
+// written.
+func uploadSite(ctx context.Context, w siteWriter, dir string) (int, error) {
+	count := 0
+	err := filepath.WalkDir(dir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, name)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		if err := w.WriteFile(ctx, filepath.ToSlash(rel), data); err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+	return count, err
+}
@@ -0,0 +1,623 @@
+// Package generator builds the static vanity import site for the Go modules
+// of one or more organizations.
+package generator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/modfile"
+)
+
+const godocURL = "https://pkg.go.dev/"
+
+// PackageInfo describes an indexed Go module and the data shown for it.
+type PackageInfo struct {
+	ImportPath     string // module path from go.mod
+	RepoImportPath string // VCS root import path (for go-import prefix)
+	IsModule       bool   // false for repositories without a go.mod
+	RepoURL        string
+	CloneURL       string
+	SourceDirURL   string // go-source directory template, with {/dir}
+	SourceFileURL  string // go-source file template, with {/dir}, {file} and {line}
+	ReadmeURL      string
+	GodocLink      string // documentation on pkg.go.dev
+	CIURL          string // page listing the CI runs
+	CIBadgeURL     string
+	CoverageURL    string // Codecov or Coveralls badge
+	Description    string
+
+	PrimaryLanguage  string
+	ContributorCount int
+	Stars            int
+	Watchers         int
+	Forks            int
+	OpenIssues       int
+	UpdatedAt        time.Time
+	LatestTag        string
+	License          string
+	SubPackages      []string // import paths of the module's other packages
+	GoReport         string   // Go Report Card grade
+	TestedGoVersions []string // Go versions used by the CI workflows
+	HasGenerators    bool     // sources contain //go:generate directives
+	DocCoverage      float64  // percentage of exported identifiers with doc comments
+	HasDocCoverage   bool     // sources declare exported identifiers, so DocCoverage is set
+	HasExamples      bool     // test files declare Example functions
+	SBOMAssetURL     string   // SBOM attached to the newest release that has one
+	Vulnerabilities  []string // OSV.dev IDs affecting the latest tag
+	DirectDeps       []string // module paths of the non-indirect go.mod requirements
+	CloneSize        int      // repository size in KB
+	HasChangelog     bool     // the repository root contains a changelog
+	DiscussionURL    string   // GitHub Discussions forum
+	DeprecatedDeps   []string // required or imported entries of deprecatedModules
+	CodeSearchURL    string   // GitHub code search for imports of the package
+	CommitsPerDay    float64  // average over the last 30 days
+	MaintainerEmail  string   // from MAINTAINERS or a go.mod comment
+	APIStability     string   // "Stable" or "Unstable" based on LatestTag
+	IsInternal       bool     // repository is designated internal
+	HomepageURL      string   // homepage set in the repository settings
+	BinaryName       string   // command installable from cmd/<BinaryName>
+	HasDocker        bool     // the repository root contains a Dockerfile
+	ContributingURL  string   // CONTRIBUTING.md in the repository root
+	TotalDownloads   int      // downloads of all release assets
+	ProxyStatus      string   // "available", "forbidden" or "not found" on proxy.golang.org
+	HasGoEmbed       bool     // sources contain //go:embed directives
+	TypesOnly        bool     // sources declare no function bodies
+	CgoRequired      bool     // sources import "C"
+	HasWASM          bool     // sources target WebAssembly
+	EmbeddedFiles    []string // patterns of the //go:embed directives
+	ProtocolBuffers  bool     // the repository contains .proto files
+	IsCLI            bool     // the repository root is package main
+	Endorsements     []string // names listed in the ENDORSED_BY file
+	MajorVersions    int      // distinct major versions above v1 among the tags
+	InstallCommand   string   // go install when IsCLI, go get otherwise
+
+	ChangelogEntries []ChangelogEntry // notes of the three newest releases
+
+	HasReleaseData     bool   // the provider listed the releases
+	LastReleaseDaysAgo int    // days since LastReleaseDate; -1 without releases
+	LastReleaseDate    string // YYYY-MM-DD of the newest release
+
+	SecondaryRepoURLs map[string]string // mirror repositories keyed by provider
+	SecurityPolicyURL string            // security policy page, when SECURITY.md exists
+}
+
+// Stats summarizes a generator run.
+type Stats struct {
+	ReposFetched int // repositories listed by the provider
+	ReposSkipped int // repositories not processed: unchanged, too old or over --max-repos
+	ReposFailed  int // repositories whose contents could not be read
+	HTMLFiles    int // pages written, including the index
+	BytesWritten int // size of the pages written
+	Duration     time.Duration
+}
+
+// Run applies opts to cfg, collects the packages it describes, writes the
+// site to cfg.OutputDir and reports what was done.
+func Run(ctx context.Context, cfg *Config, opts ...Option) (stats Stats, err error) {
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	start := time.Now()
+	defer func() { stats.Duration = time.Since(start) }()
+
+	if err := runHook(ctx, "pregenerate", cfg.PregenerateHook); err != nil {
+		return stats, err
+	}
+
+	packages, err := collect(ctx, cfg, &stats)
+	if err != nil {
+		return stats, err
+	}
+	if cfg.CleanBefore {
+		log.Printf("Removing output directory %s", cfg.OutputDir)
+		if err := os.RemoveAll(cfg.OutputDir); err != nil {
+			return stats, fmt.Errorf("failed to clean output directory: %v", err)
+		}
+	}
+	if err := os.MkdirAll(cfg.OutputDir, 0755); err != nil {
+		return stats, fmt.Errorf("failed to create output directory: %v", err)
+	}
+
+	w := &htmlWriter{outputDir: cfg.OutputDir, baseDomain: cfg.BaseDomain, minify: cfg.HTMLMinify, ogImages: cfg.OGImages, noRefresh: cfg.NoHTMLRefresh}
+	if cfg.ExtraCSSFile != "" {
+		css, err := os.ReadFile(cfg.ExtraCSSFile)
+		if err != nil {
+			return stats, fmt.Errorf("failed to read extra CSS: %v", err)
+		}
+		if bytes.Contains(bytes.ToLower(css), []byte("</style")) {
+			return stats, fmt.Errorf("extra CSS file %s must not contain a closing style tag", cfg.ExtraCSSFile)
+		}
+		w.extraCSS = string(css)
+	}
+	pipeline, err := newPipeline(cfg, w)
+	if err != nil {
+		return stats, err
+	}
+	packages = pipeline.Run(ctx, packages)
+
+	// 生成主页
+	listed := packages
+	if !cfg.IncludeInternal {
+		listed = slices.DeleteFunc(slices.Clone(packages), func(pkg PackageInfo) bool { return pkg.IsInternal })
+	}
+	log.Printf("\nGenerating index HTML with %d package(s)", len(listed))
+	if err := generateIndexHTML(w, listed); err != nil {
+		log.Printf("Error generating index HTML: %v", err)
+	} else {
+		log.Printf("✓ Successfully generated index HTML")
+	}
+
+	if n, err := generateListingPages(w, packages, listed); err != nil {
+		log.Printf("Error generating directory listings: %v", err)
+	} else if n > 0 {
+		log.Printf("✓ Successfully generated %d directory listing(s)", n)
+	}
+
+	if err := generatePackagesJSON(listed, cfg.OutputDir); err != nil {
+		log.Printf("Error generating packages.json: %v", err)
+	} else {
+		log.Printf("✓ Successfully generated packages.json and packages.json.gz")
+	}
+
+	if err := generateTOC(listed, cfg.OutputDir); err != nil {
+		log.Printf("Error generating toc.json: %v", err)
+	} else {
+		log.Printf("✓ Successfully generated toc.json")
+	}
+
+	if err := generateSitemap(listed, cfg.BaseDomain, cfg.OutputDir); err != nil {
+		log.Printf("Error generating sitemap: %v", err)
+	} else {
+		log.Printf("✓ Successfully generated sitemap_index.xml")
+	}
+
+	if err := generateWebManifest(cfg.BaseDomain, cfg.OutputDir); err != nil {
+		log.Printf("Error generating web manifest: %v", err)
+	} else {
+		log.Printf("✓ Successfully generated web manifest")
+	}
+
+	if err := generateHealthCheck(cfg.OutputDir, len(packages)); err != nil {
+		log.Printf("Error generating health.json: %v", err)
+	} else {
+		log.Printf("✓ Successfully generated health.json")
+	}
+
+	log.Printf("\n=== Generation Complete ===")
+	log.Printf("Total packages processed: %d", len(packages))
+	log.Printf("Index page: %s", filepath.Join(cfg.OutputDir, "index.html"))
+	if w.minify {
+		log.Printf("Minified HTML: %d -> %d bytes (saved %d)", w.renderedBytes, w.writtenBytes, w.renderedBytes-w.writtenBytes)
+	}
+
+	stats.HTMLFiles = w.pages
+	stats.BytesWritten = w.writtenBytes
+
+	if err := runHook(ctx, "postgenerate", cfg.PostgenerateHook); err != nil {
+		return stats, err
+	}
+
+	// Hashed after the hook, which may rewrite the output
+	if cfg.ContentHash {
+		if sum, err := writeContentHash(cfg.OutputDir); err != nil {
+			log.Printf("Error generating %s: %v", contentHashName, err)
+		} else {
+			log.Printf("✓ Successfully generated %s (%s)", contentHashName, sum)
+		}
+	}
+
+	if cfg.GCSBucket != "" {
+		gcs, err := newGCSWriter(ctx, cfg.GCSBucket, cfg.GCSPrefix)
+		if err != nil {
+			return stats, err
+		}
+		count, err := uploadSite(ctx, gcs, cfg.OutputDir)
+		if err != nil {
+			return stats, fmt.Errorf("failed to upload site to gs://%s: %v", cfg.GCSBucket, err)
+		}
+		log.Printf("Uploaded %d file(s) to gs://%s/%s", count, cfg.GCSBucket, cfg.GCSPrefix)
+	}
+	return stats, nil
+}
+
+// Collect fetches every module of the configured organizations whose path
+// lives under cfg.BaseDomain.
+func Collect(ctx context.Context, cfg *Config) ([]PackageInfo, error) {
+	pipeline, err := newPipeline(cfg, nil)
+	if err != nil {
+		return nil, err
+	}
+	packages, err := collect(ctx, cfg, &Stats{})
+	if err != nil {
+		return nil, err
+	}
+	return pipeline.Run(ctx, packages), nil
+}
+
+// collect returns the packages of the configured repositories as read from
+// the provider, before they go through the pipeline.
+func collect(ctx context.Context, cfg *Config, stats *Stats) ([]PackageInfo, error) {
+	client, err := newVCSClient(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// 获取组织下的所有仓库
+	var repos []*Repository
+	if cfg.User != "" {
+		lister, ok := client.(userRepoLister)
+		if !ok {
+			return nil, fmt.Errorf("vcs provider %q cannot list user repositories", cfg.VCSProvider)
+		}
+		log.Printf("Fetching repositories for user: %s", cfg.User)
+		if repos, err = lister.ListUserRepos(ctx, cfg.User); err != nil {
+			return nil, fmt.Errorf("error listing repositories: %v", err)
+		}
+	} else {
+		for _, org := range cfg.Orgs {
+			log.Printf("Fetching repositories for organization: %s", org)
+			orgRepos, err := client.ListRepos(ctx, org)
+			if err != nil {
+				return nil, fmt.Errorf("error listing repositories: %v", err)
+			}
+			repos = append(repos, orgRepos...)
+		}
+	}
+	log.Printf("Found %d repositories", len(repos))
+	stats.ReposFetched = len(repos)
+
+	state := &generatorState{Config: stateFingerprint(cfg), Repos: make(map[string]repoState)}
+	if cfg.StateFile != "" {
+		if state, err = loadState(cfg.StateFile, stateFingerprint(cfg)); err != nil {
+			return nil, err
+		}
+	}
+
+	var packages []PackageInfo
+	indexedRepos := 0
+	after, err := cfg.afterDate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid after date: %v", err)
+	}
+
+	for i, repo := range repos {
+		if cfg.BaseBranch != "" {
+			repo.Ref = cfg.BaseBranch
+		}
+		if cfg.MaxRepos > 0 && indexedRepos >= cfg.MaxRepos {
+			log.Printf("Reached --max-repos=%d, skipping the remaining %d repositories", cfg.MaxRepos, len(repos)-i)
+			stats.ReposSkipped += len(repos) - i
+			break
+		}
+
+		if !after.IsZero() && repo.PushedAt.Before(after) {
+			// Older repositories stay on the site with the packages of
+			// the previous run; only their processing is skipped.
+			cached, ok := state.cached(repo)
+			if ok {
+				log.Printf("Skipping repository %s: last pushed %s, before %s, reusing %d package(s)", repo.Name, repo.PushedAt.Format(time.DateOnly), cfg.AfterDate, len(cached))
+			} else {
+				log.Printf("Skipping repository %s: last pushed %s, before %s, not in the state file", repo.Name, repo.PushedAt.Format(time.DateOnly), cfg.AfterDate)
+			}
+			packages = append(packages, cached...)
+			if len(cached) > 0 {
+				indexedRepos++
+			}
+			stats.ReposSkipped++
+			continue
+		}
+
+		log.Printf("Processing repository: %s", repo.Name)
+		if repo.Language == "Go" {
+			log.Printf("  Found Go repository: %s", repo.Name)
+		}
+
+		if cached, ok := state.unchanged(repo); ok {
+			log.Printf("  Unchanged since last run, reusing %d package(s)", len(cached))
+			packages = append(packages, cached...)
+			if len(cached) > 0 {
+				indexedRepos++
+			}
+			stats.ReposSkipped++
+			continue
+		}
+
+		repoPackages, err := collectRepo(ctx, client, cfg, repo)
+		if err != nil {
+			log.Printf("Error getting contents for %s: %v", repo.Name, err)
+			stats.ReposFailed++
+			continue
+		}
+		state.record(repo, repoPackages)
+		packages = append(packages, repoPackages...)
+		if len(repoPackages) > 0 {
+			indexedRepos++
+		}
+
+		if rate, ok := rateLimit(ctx, client); ok {
+			log.Printf("  Rate limit: %d/%d remaining, resets at %s", rate.Remaining, rate.Limit, rate.Reset.Format(time.RFC3339))
+			if cfg.RateLimitBuffer > 0 && rate.Remaining < cfg.RateLimitBuffer {
+				saveState(cfg, state)
+				return nil, fmt.Errorf("API rate limit nearly exhausted (%d of %d remaining, resets at %s); stopping to keep the existing output",
+					rate.Remaining, rate.Limit, rate.Reset.Format(time.RFC3339))
+			}
+		}
+	}
+
+	saveState(cfg, state)
+
+	// Deduplicate packages served by several organizations
+	seen := make(map[string]bool)
+	unique := packages[:0]
+	for _, pkg := range packages {
+		if seen[pkg.ImportPath] {
+			log.Printf("Skipping duplicate package %s from %s", pkg.ImportPath, pkg.RepoURL)
+			continue
+		}
+		seen[pkg.ImportPath] = true
+		unique = append(unique, pkg)
+	}
+	packages = unique
+
+	return packages, nil
+}
+
+func saveState(cfg *Config, state *generatorState) {
+	if cfg.StateFile == "" {
+		return
+	}
+	if err := state.save(cfg.StateFile); err != nil {
+		log.Printf("Error saving state: %v", err)
+	}
+}
+
+// collectRepo returns the packages of a single repository.
+func collectRepo(ctx context.Context, client VCSClient, cfg *Config, repo *Repository) ([]PackageInfo, error) {
+	// Get repository file tree
+	tree, err := client.GetFileTree(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]bool)
+	moduleDirs := make(map[string]bool)
+	for _, entry := range tree {
+		if entry.Type != "file" {
+			continue
+		}
+		files[entry.Path] = true
+		if path.Base(entry.Path) == "go.mod" {
+			moduleDirs[path.Dir(entry.Path)] = true
+		}
+	}
+	if len(moduleDirs) == 0 && !(cfg.IncludeNonModule && repo.Language == "Go") {
+		log.Printf("  No go.mod found in %s", repo.Name)
+		return nil, nil
+	}
+
+	var packages []PackageInfo
+
+	tags := listTags(ctx, client, repo)
+	fetchDetails(ctx, client, repo)
+
+	// Fields shared by every package of the repository
+	repoInfo := PackageInfo{
+		IsModule:         len(moduleDirs) > 0,
+		ContributorCount: countContributors(ctx, client, repo),
+		LatestTag:        latestTag(tags, cfg.TagPrefix),
+		HasChangelog:     hasChangelog(files),
+		CommitsPerDay:    commitsPerDay(ctx, client, repo, 30),
+		MaintainerEmail:  maintainerEmail(ctx, client, repo, files),
+		Endorsements:     endorsements(ctx, client, repo, files),
+		HasDocker:        hasDocker(files),
+		ProtocolBuffers:  hasProto(tree),
+		MajorVersions:    majorVersions(tags, cfg.TagPrefix),
+	}
+	setRepositoryFields(&repoInfo, repo)
+	repoInfo.APIStability = apiStability(repoInfo.LatestTag)
+	if hasReadme(tree) {
+		repoInfo.ReadmeURL = repo.ReadmeURL
+	}
+	if files["CONTRIBUTING.md"] {
+		repoInfo.ContributingURL = repo.webURL("blob", "/CONTRIBUTING.md")
+	}
+	repoInfo.SecurityPolicyURL = securityPolicyURL(files, repo)
+	if repo.Discussions {
+		repoInfo.DiscussionURL = repo.HTMLURL + "/discussions"
+	}
+	repoInfo.CIURL, repoInfo.CIBadgeURL = ciBadge(tree, files, repo)
+	repoInfo.CoverageURL = coverageBadgeURL(files, repo)
+	repoInfo.TestedGoVersions = testedGoVersions(ctx, client, repo, tree)
+
+	releases, ok := listReleases(ctx, client, repo)
+	repoInfo.HasReleaseData = ok
+	repoInfo.SBOMAssetURL = sbomAssetURL(releases)
+	repoInfo.TotalDownloads = totalDownloads(releases)
+	repoInfo.ChangelogEntries = changelogEntries(releases, 3)
+	repoInfo.LastReleaseDate = lastReleaseDate(releases)
+
+	sources := fetchSources(ctx, client, repo, tree)
+	repoInfo.HasGenerators = hasDirective(sources, "//go:generate ")
+	repoInfo.DocCoverage, repoInfo.HasDocCoverage = docCoverage(sources)
+	repoInfo.HasExamples = hasExamples(sources)
+	repoInfo.HasGoEmbed = hasDirective(sources, "//go:embed ")
+	if repoInfo.HasGoEmbed {
+		repoInfo.EmbeddedFiles = embedPatterns(sources)
+	}
+	repoInfo.TypesOnly = typesOnly(sources)
+	repoInfo.CgoRequired = importsC(sources)
+	repoInfo.HasWASM = hasWASM(sources)
+	repoInfo.DeprecatedDeps = deprecatedDeps(nil, importPaths(sources))
+	cli := isCLI(ctx, client, repo, tree, sources)
+
+	if len(moduleDirs) == 0 {
+		// Pre-modules repository, served under a path derived from its name
+		pkgInfo := repoInfo
+		pkgInfo.ImportPath = cfg.BaseDomain + "/" + repo.Name
+		pkgInfo.RepoImportPath = pkgInfo.ImportPath
+		pkgInfo.IsCLI = cli
+		log.Printf("  Non-module repository, using legacy import path %s", pkgInfo.ImportPath)
+		return []PackageInfo{pkgInfo}, nil
+	}
+
+	// Check root go.mod
+	log.Printf("  Checking root go.mod for %s", repo.Name)
+	if files["go.mod"] {
+		if fileContent, err := client.GetFileContent(ctx, repo, "go.mod"); err == nil {
+			moduleName := parseModuleName(fileContent)
+			log.Printf("  Root module: %s", moduleName)
+			if strings.HasPrefix(moduleName, cfg.BaseDomain) {
+				pkgInfo := repoInfo
+				pkgInfo.ImportPath = moduleName
+				pkgInfo.RepoImportPath = moduleName
+				pkgInfo.DirectDeps = directDeps(fileContent)
+				pkgInfo.DeprecatedDeps = deprecatedDeps(slices.Clone(pkgInfo.DeprecatedDeps), pkgInfo.DirectDeps)
+				pkgInfo.BinaryName = binaryName(tree, moduleDirs)
+				pkgInfo.IsCLI = cli
+				if pkgInfo.MaintainerEmail == "" {
+					pkgInfo.MaintainerEmail = commentEmail(fileContent)
+				}
+				for _, dir := range packageDirs(tree, moduleDirs) {
+					if cfg.includeSubpackage(dir) {
+						pkgInfo.SubPackages = append(pkgInfo.SubPackages, moduleName+"/"+dir)
+					}
+				}
+				packages = append(packages, pkgInfo)
+			} else {
+				log.Printf("  Skipping root module: doesn't start with %s", cfg.BaseDomain)
+			}
+		} else {
+			log.Printf("  Failed to read root go.mod: %v", err)
+		}
+	} else {
+		log.Printf("  No root go.mod found for %s", repo.Name)
+	}
+
+	// Check first-level subdirectories for go.mod (sub-modules)
+	indexedDirs := make(map[string]bool)
+	for _, entry := range tree {
+		if entry.Type != "dir" || strings.Contains(entry.Path, "/") {
+			continue
+		}
+		subDir := entry.Path
+		if !files[subDir+"/go.mod"] {
+			continue
+		}
+		indexedDirs[subDir] = true
+		if pkgInfo, ok := subModule(ctx, client, cfg, repo, repoInfo, subDir); ok {
+			packages = append(packages, pkgInfo)
+		}
+	}
+
+	// Check go.work for workspace modules in deeper directories
+	if files["go.work"] {
+		for _, dir := range workspaceDirs(ctx, client, repo) {
+			if dir == "." || strings.HasPrefix(dir, "../") || indexedDirs[dir] {
+				continue
+			}
+			indexedDirs[dir] = true
+			if pkgInfo, ok := subModule(ctx, client, cfg, repo, repoInfo, dir); ok {
+				packages = append(packages, pkgInfo)
+			}
+		}
+	}
+
+	return packages, nil
+}
+
+// subModule reads the go.mod of a module nested in dir and returns its
+// package, or false if it cannot be read or lives outside cfg.BaseDomain.
+func subModule(ctx context.Context, client VCSClient, cfg *Config, repo *Repository, repoInfo PackageInfo, dir string) (PackageInfo, bool) {
+	fileContent, err := client.GetFileContent(ctx, repo, dir+"/go.mod")
+	if err != nil {
+		log.Printf("  Failed to read %s/go.mod: %v", dir, err)
+		return PackageInfo{}, false
+	}
+	moduleName := parseModuleName(fileContent)
+	log.Printf("  Sub-module found: %s (in %s/)", moduleName, dir)
+	if !strings.HasPrefix(moduleName, cfg.BaseDomain) {
+		log.Printf("  Skipping sub-module %s: doesn't start with %s", moduleName, cfg.BaseDomain)
+		return PackageInfo{}, false
+	}
+
+	pkgInfo := repoInfo
+	pkgInfo.ImportPath = moduleName
+	pkgInfo.RepoImportPath = strings.TrimSuffix(moduleName, "/"+dir)
+	pkgInfo.DirectDeps = directDeps(fileContent)
+	pkgInfo.DeprecatedDeps = deprecatedDeps(slices.Clone(pkgInfo.DeprecatedDeps), pkgInfo.DirectDeps)
+	if pkgInfo.MaintainerEmail == "" {
+		pkgInfo.MaintainerEmail = commentEmail(fileContent)
+	}
+	return pkgInfo, true
+}
+
+// workspaceDirs returns the module directories listed by the use directives
+// of the repository's root go.work.
+func workspaceDirs(ctx context.Context, client VCSClient, repo *Repository) []string {
+	content, err := client.GetFileContent(ctx, repo, "go.work")
+	if err != nil {
+		log.Printf("  Failed to read go.work: %v", err)
+		return nil
+	}
+	work, err := modfile.ParseWork("go.work", []byte(content), nil)
+	if err != nil {
+		log.Printf("  Failed to parse go.work: %v", err)
+		return nil
+	}
+	var dirs []string
+	for _, use := range work.Use {
+		dirs = append(dirs, path.Clean(use.Path))
+	}
+	return dirs
+}
+
+// installCommand returns the command fetching pkg: go install when the
+// package itself is a command, go get otherwise. Commands shipped under
+// cmd/<BinaryName> are shown by the templates as a second command.
+func installCommand(pkg PackageInfo) string {
+	if pkg.IsCLI {
+		return "go install " + pkg.ImportPath + "@latest"
+	}
+	return "go get " + pkg.ImportPath
+}
+
+// codeSearchURL returns a GitHub code search for files importing importPath.
+func codeSearchURL(importPath string) string {
+	return "https://github.com/search?q=" + url.QueryEscape(`import "`+importPath+`"`) + "&type=code"
+}
+
+// directDeps returns the module paths of the go.mod requirements that are
+// not marked as indirect.
+func directDeps(content string) []string {
+	f, err := modfile.ParseLax("go.mod", []byte(content), nil)
+	if err != nil {
+		log.Printf("  Failed to parse go.mod requirements: %v", err)
+		return nil
+	}
+	var deps []string
+	for _, req := range f.Require {
+		if !req.Indirect {
+			deps = append(deps, req.Mod.Path)
+		}
+	}
+	return deps
+}
+
+func parseModuleName(content string) string {
+	lines := strings.Split(content, "\n")
+	for _, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "module ") {
+			return strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "module "))
+		}
+	}
+	return ""
+}
@@ -0,0 +1,315 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+	"github.com/zalando/go-keyring"
+	"golang.org/x/oauth2"
+)
+
+// Keychain entry read by --keychain.
+const (
+	keychainService = "blksails-pkg-index"
+	keychainUser    = "github-token"
+)
+
+type githubClient struct {
+	client *github.Client
+
+	perPage int               // page size of list requests, at most 100
+	graphql bool              // list repositories with the GraphQL API
+	gomods  map[string]string // go.mod contents fetched by GraphQL, by gomodKey
+}
+
+func newGitHubClient(ctx context.Context, ts oauth2.TokenSource, apiBaseURL string, graphql bool) (*githubClient, error) {
+	tc := oauth2.NewClient(ctx, ts)
+	tc.Transport = &loggingTransport{base: tc.Transport}
+	client := github.NewClient(tc)
+	if apiBaseURL != "" {
+		baseURL, err := url.Parse(strings.TrimSuffix(apiBaseURL, "/") + "/")
+		if err != nil {
+			return nil, fmt.Errorf("invalid API base URL: %v", err)
+		}
+		client.BaseURL = baseURL
+	}
+	return &githubClient{client: client, perPage: 100, graphql: graphql, gomods: make(map[string]string)}, nil
+}
+
+// githubToken returns the token stored in the OS keychain when useKeychain is
+// set, falling back to the GITHUB_TOKEN environment variable.
+func githubToken(useKeychain bool) string {
+	if useKeychain {
+		token, err := keyring.Get(keychainService, keychainUser)
+		if err == nil {
+			return token
+		}
+		log.Printf("Keychain lookup failed, falling back to GITHUB_TOKEN: %v", err)
+	}
+	return os.Getenv("GITHUB_TOKEN")
+}
+
+func (c *githubClient) ListRepos(ctx context.Context, owner string) ([]*Repository, error) {
+	if c.graphql {
+		return c.listReposGraphQL(ctx, graphqlOrgReposQuery, owner)
+	}
+	var repos []*Repository
+	opt := &github.RepositoryListByOrgOptions{
+		ListOptions: github.ListOptions{PerPage: c.perPage},
+	}
+	for {
+		page, resp, err := c.client.Repositories.ListByOrg(ctx, owner, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, repo := range page {
+			repos = append(repos, convertRepository(owner, repo))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return repos, nil
+}
+
+func (c *githubClient) ListUserRepos(ctx context.Context, user string) ([]*Repository, error) {
+	if c.graphql {
+		return c.listReposGraphQL(ctx, graphqlUserReposQuery, user)
+	}
+	var repos []*Repository
+	opt := &github.RepositoryListOptions{
+		Type:        "owner",
+		ListOptions: github.ListOptions{PerPage: c.perPage},
+	}
+	for {
+		page, resp, err := c.client.Repositories.List(ctx, user, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, repo := range page {
+			repos = append(repos, convertRepository(user, repo))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return repos, nil
+}
+
+func convertRepository(owner string, repo *github.Repository) *Repository {
+	return &Repository{
+		Provider:      "github",
+		Owner:         owner,
+		Name:          repo.GetName(),
+		Description:   repo.GetDescription(),
+		Language:      repo.GetLanguage(),
+		HTMLURL:       repo.GetHTMLURL(),
+		CloneURL:      repo.GetCloneURL(),
+		ReadmeURL:     repo.GetHTMLURL() + "#readme",
+		DefaultBranch: repo.GetDefaultBranch(),
+		Ref:           repo.GetDefaultBranch(),
+		Stars:         repo.GetStargazersCount(),
+		Forks:         repo.GetForksCount(),
+		OpenIssues:    repo.GetOpenIssuesCount(),
+		UpdatedAt:     repo.GetUpdatedAt().Time,
+		PushedAt:      repo.GetPushedAt().Time,
+		License:       repo.GetLicense().GetSPDXID(),
+		Size:          repo.GetSize(),
+		Topics:        repo.Topics,
+		Homepage:      repo.GetHomepage(),
+	}
+}
+
+func (c *githubClient) GetFileContent(ctx context.Context, repo *Repository, path string) (string, error) {
+	if content, ok := c.gomods[gomodKey(repo)]; ok && path == "go.mod" {
+		return content, nil
+	}
+	content, _, _, err := c.client.Repositories.GetContents(ctx, repo.Owner, repo.Name, path,
+		&github.RepositoryContentGetOptions{Ref: repo.Ref})
+	if err != nil {
+		return "", err
+	}
+	if content == nil {
+		return "", fmt.Errorf("%s is a directory", path)
+	}
+	return content.GetContent()
+}
+
+func (c *githubClient) GetFileTree(ctx context.Context, repo *Repository) ([]TreeEntry, error) {
+	tree, _, err := c.client.Git.GetTree(ctx, repo.Owner, repo.Name, repo.Ref, true)
+	if err != nil {
+		return nil, err
+	}
+	if tree.GetTruncated() {
+		log.Printf("  Warning: file tree of %s is truncated", repo.Name)
+	}
+	entries := make([]TreeEntry, 0, len(tree.Entries))
+	for _, entry := range tree.Entries {
+		switch entry.GetType() {
+		case "blob":
+			entries = append(entries, TreeEntry{Path: entry.GetPath(), Type: "file"})
+		case "tree":
+			entries = append(entries, TreeEntry{Path: entry.GetPath(), Type: "dir"})
+		}
+	}
+	return entries, nil
+}
+
+func (c *githubClient) CountContributors(ctx context.Context, repo *Repository) (int, error) {
+	count := 0
+	opt := &github.ListContributorsOptions{
+		Anon:        "false",
+		ListOptions: github.ListOptions{PerPage: c.perPage},
+	}
+	for {
+		page, resp, err := c.client.Repositories.ListContributors(ctx, repo.Owner, repo.Name, opt)
+		if err != nil {
+			return 0, err
+		}
+		count += len(page)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return count, nil
+}
+
+// FetchDetails reads the subscribers and whether discussions are enabled
+// from the repository endpoint. List endpoints include neither, their
+// watchers_count is an alias of the stars, and the go-github version in use
+// does not expose has_discussions. The GraphQL listing has both.
+func (c *githubClient) FetchDetails(ctx context.Context, repo *Repository) error {
+	if c.graphql {
+		return nil
+	}
+	req, err := c.client.NewRequest("GET", "repos/"+repo.Owner+"/"+repo.Name, nil)
+	if err != nil {
+		return err
+	}
+	var body struct {
+		SubscribersCount int  `json:"subscribers_count"`
+		HasDiscussions   bool `json:"has_discussions"`
+	}
+	if _, err := c.client.Do(ctx, req, &body); err != nil {
+		return err
+	}
+	repo.Watchers = body.SubscribersCount
+	repo.Discussions = body.HasDiscussions
+	return nil
+}
+
+func (c *githubClient) CountCommitsSince(ctx context.Context, repo *Repository, since time.Time) (int, error) {
+	count := 0
+	opt := &github.CommitsListOptions{
+		SHA:         repo.Ref,
+		Since:       since,
+		ListOptions: github.ListOptions{PerPage: c.perPage},
+	}
+	for {
+		page, resp, err := c.client.Repositories.ListCommits(ctx, repo.Owner, repo.Name, opt)
+		if err != nil {
+			return 0, err
+		}
+		count += len(page)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return count, nil
+}
+
+func (c *githubClient) ListTags(ctx context.Context, repo *Repository) ([]string, error) {
+	var tags []string
+	opt := &github.ListOptions{PerPage: c.perPage}
+	for {
+		page, resp, err := c.client.Repositories.ListTags(ctx, repo.Owner, repo.Name, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range page {
+			tags = append(tags, tag.GetName())
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return tags, nil
+}
+
+func (c *githubClient) ListReleases(ctx context.Context, repo *Repository) ([]Release, error) {
+	var releases []Release
+	opt := &github.ListOptions{PerPage: c.perPage}
+	for {
+		page, resp, err := c.client.Repositories.ListReleases(ctx, repo.Owner, repo.Name, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range page {
+			release := Release{
+				Tag:         r.GetTagName(),
+				PublishedAt: r.GetPublishedAt().Time,
+				Body:        r.GetBody(),
+			}
+			for _, asset := range r.Assets {
+				release.Assets = append(release.Assets, ReleaseAsset{
+					Name:          asset.GetName(),
+					DownloadURL:   asset.GetBrowserDownloadURL(),
+					DownloadCount: asset.GetDownloadCount(),
+				})
+			}
+			releases = append(releases, release)
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return releases, nil
+}
+
+func (c *githubClient) DownloadArchive(ctx context.Context, repo *Repository) (io.ReadCloser, error) {
+	link, _, err := c.client.Repositories.GetArchiveLink(ctx, repo.Owner, repo.Name, github.Tarball,
+		&github.RepositoryContentGetOptions{Ref: repo.Ref}, false)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("GET %s: unexpected status %s", link.Path, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+func (c *githubClient) RateLimit(ctx context.Context) (RateLimit, error) {
+	limits, _, err := c.client.RateLimits(ctx)
+	if err != nil {
+		return RateLimit{}, err
+	}
+	core := limits.GetCore()
+	return RateLimit{
+		Limit:     core.Limit,
+		Remaining: core.Remaining,
+		Reset:     core.Reset.Time,
+	}, nil
+}
@@ -0,0 +1,146 @@
+package generator
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	githubAPIURL = "https://api.github.com/"
+
+	// DefaultGitHubAppJWTTTL stays below the ten minute maximum GitHub
+	// accepts for the exp claim of an app JWT.
+	DefaultGitHubAppJWTTTL = 9*time.Minute + 50*time.Second
+
+	// installationTokenRefresh is how long before expiry an installation
+	// token is replaced by a new one.
+	installationTokenRefresh = 5 * time.Minute
+)
+
+// appTokenSource exchanges a GitHub App JWT for installation access tokens.
+type appTokenSource struct {
+	appID          int64
+	installationID int64
+	key            *rsa.PrivateKey
+	jwtTTL         time.Duration
+	baseURL        string
+	httpClient     *http.Client
+}
+
+// newAppTokenSource returns a token source for cfg's GitHub App installation
+// that requests a new installation token shortly before the current one
+// expires, so long runs keep working.
+func newAppTokenSource(cfg *Config, httpClient *http.Client) (oauth2.TokenSource, error) {
+	pemData, err := os.ReadFile(cfg.GitHubAppPrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GitHub App private key: %v", err)
+	}
+	key, err := parseRSAPrivateKey(pemData)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GitHub App private key: %v", err)
+	}
+	baseURL := githubAPIURL
+	if cfg.APIBaseURL != "" {
+		baseURL = strings.TrimSuffix(cfg.APIBaseURL, "/") + "/"
+	}
+	src := &appTokenSource{
+		appID:          cfg.GitHubAppID,
+		installationID: cfg.GitHubAppInstallationID,
+		key:            key,
+		jwtTTL:         cfg.GitHubAppJWTTTL,
+		baseURL:        baseURL,
+		httpClient:     httpClient,
+	}
+	if src.jwtTTL == 0 {
+		src.jwtTTL = DefaultGitHubAppJWTTTL
+	}
+	return oauth2.ReuseTokenSourceWithExpiry(nil, src, installationTokenRefresh), nil
+}
+
+func (s *appTokenSource) Token() (*oauth2.Token, error) {
+	jwt, err := s.jwt(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	endpoint := s.baseURL + "app/installations/" + strconv.FormatInt(s.installationID, 10) + "/access_tokens"
+	req, err := http.NewRequest(http.MethodPost, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("failed to create installation token: unexpected status %s", resp.Status)
+	}
+
+	var body struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode installation token: %v", err)
+	}
+	return &oauth2.Token{AccessToken: body.Token, TokenType: "token", Expiry: body.ExpiresAt}, nil
+}
+
+// jwt returns an RS256 signed JWT identifying the app. iat is backdated by a
+// minute to allow for clock drift, as GitHub recommends.
+func (s *appTokenSource) jwt(now time.Time) (string, error) {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, err := json.Marshal(map[string]int64{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(s.jwtTTL).Unix(),
+		"iss": s.appID,
+	})
+	if err != nil {
+		return "", err
+	}
+	unsigned := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign GitHub App JWT: %v", err)
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// parseRSAPrivateKey decodes a PEM encoded PKCS #1 or PKCS #8 RSA key, the
+// formats GitHub offers for app private keys.
+func parseRSAPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("not an RSA key")
+	}
+	return rsaKey, nil
+}
@@ -0,0 +1,159 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// graphqlReposQuery fetches a page of the repositories of an owner along
+// with their go.mod on the default branch. It is completed with the owner
+// field, organization or user, and extra arguments of repositories.
+const graphqlReposQuery = `query($owner: String!, $first: Int!, $cursor: String) {
+  owner: %s(login: $owner) {
+    repositories(first: $first, after: $cursor%s) {
+      pageInfo { hasNextPage endCursor }
+      nodes {
+        name
+        description
+        url
+        homepageUrl
+        primaryLanguage { name }
+        defaultBranchRef { name }
+        stargazerCount
+        forkCount
+        watchers { totalCount }
+        hasDiscussionsEnabled
+        issues(states: OPEN) { totalCount }
+        updatedAt
+        pushedAt
+        diskUsage
+        licenseInfo { spdxId }
+        repositoryTopics(first: 20) { nodes { topic { name } } }
+        gomod: object(expression: "HEAD:go.mod") { ... on Blob { text } }
+      }
+    }
+  }
+}`
+
+type graphqlRepo struct {
+	Name             string
+	Description      string
+	URL              string
+	HomepageURL      string
+	PrimaryLanguage  struct{ Name string }
+	DefaultBranchRef struct {
+		Name string
+	}
+	StargazerCount   int
+	ForkCount        int
+	Watchers         struct{ TotalCount int }
+	HasDiscussions   bool `json:"hasDiscussionsEnabled"`
+	Issues           struct{ TotalCount int }
+	UpdatedAt        time.Time
+	PushedAt         time.Time
+	DiskUsage        int
+	LicenseInfo      struct{ SpdxID string }
+	RepositoryTopics struct {
+		Nodes []struct {
+			Topic struct{ Name string }
+		}
+	}
+	Gomod *struct{ Text string }
+}
+
+type graphqlReposResponse struct {
+	Data struct {
+		Owner struct {
+			Repositories struct {
+				PageInfo struct {
+					HasNextPage bool
+					EndCursor   string
+				}
+				Nodes []graphqlRepo
+			}
+		}
+	}
+	Errors []struct{ Message string }
+}
+
+// Queries listing the repositories of an organization and those owned by a
+// user account, matching the REST type=owner listing.
+var (
+	graphqlOrgReposQuery  = fmt.Sprintf(graphqlReposQuery, "organization", "")
+	graphqlUserReposQuery = fmt.Sprintf(graphqlReposQuery, "user", ", ownerAffiliations: [OWNER]")
+)
+
+// listReposGraphQL lists repositories with one GraphQL query per page of
+// repositories instead of a REST call per repository and file. The go.mod
+// contents are kept so GetFileContent can serve them without another request.
+func (c *githubClient) listReposGraphQL(ctx context.Context, query, owner string) ([]*Repository, error) {
+	// GitHub Enterprise serves REST under /api/v3/ and GraphQL under /api/graphql.
+	endpoint := "graphql"
+	if strings.HasSuffix(c.client.BaseURL.Path, "/v3/") {
+		endpoint = "../graphql"
+	}
+
+	var repos []*Repository
+	var cursor *string
+	for {
+		req, err := c.client.NewRequest("POST", endpoint, map[string]any{
+			"query":     query,
+			"variables": map[string]any{"owner": owner, "first": c.perPage, "cursor": cursor},
+		})
+		if err != nil {
+			return nil, err
+		}
+		var resp graphqlReposResponse
+		if _, err := c.client.Do(ctx, req, &resp); err != nil {
+			return nil, err
+		}
+		if len(resp.Errors) > 0 {
+			return nil, fmt.Errorf("graphql: %s", resp.Errors[0].Message)
+		}
+
+		page := resp.Data.Owner.Repositories
+		for _, r := range page.Nodes {
+			repo := &Repository{
+				Provider:      "github",
+				Owner:         owner,
+				Name:          r.Name,
+				Description:   r.Description,
+				Language:      r.PrimaryLanguage.Name,
+				HTMLURL:       r.URL,
+				CloneURL:      r.URL + ".git",
+				ReadmeURL:     r.URL + "#readme",
+				DefaultBranch: r.DefaultBranchRef.Name,
+				Ref:           r.DefaultBranchRef.Name,
+				Stars:         r.StargazerCount,
+				Watchers:      r.Watchers.TotalCount,
+				Discussions:   r.HasDiscussions,
+				Forks:         r.ForkCount,
+				OpenIssues:    r.Issues.TotalCount,
+				UpdatedAt:     r.UpdatedAt,
+				PushedAt:      r.PushedAt,
+				License:       r.LicenseInfo.SpdxID,
+				Size:          r.DiskUsage,
+				Homepage:      r.HomepageURL,
+			}
+			for _, node := range r.RepositoryTopics.Nodes {
+				repo.Topics = append(repo.Topics, node.Topic.Name)
+			}
+			if r.Gomod != nil {
+				c.gomods[gomodKey(repo)] = r.Gomod.Text
+			}
+			repos = append(repos, repo)
+		}
+		if !page.PageInfo.HasNextPage {
+			break
+		}
+		cursor = &page.PageInfo.EndCursor
+	}
+	return repos, nil
+}
+
+// gomodKey identifies the go.mod of a repository at repo.Ref.
+func gomodKey(repo *Repository) string {
+	return repo.Owner + "/" + repo.Name + "@" + repo.Ref
+}
@@ -0,0 +1,210 @@
+package generator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const gitlabAPIURL = "https://gitlab.com/api/v4"
+
+type gitlabClient struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+type gitlabProject struct {
+	Name              string    `json:"name"`
+	Path              string    `json:"path"`
+	PathWithNamespace string    `json:"path_with_namespace"`
+	Description       string    `json:"description"`
+	WebURL            string    `json:"web_url"`
+	HTTPURLToRepo     string    `json:"http_url_to_repo"`
+	ReadmeURL         string    `json:"readme_url"`
+	DefaultBranch     string    `json:"default_branch"`
+	StarCount         int       `json:"star_count"`
+	ForksCount        int       `json:"forks_count"`
+	OpenIssuesCount   int       `json:"open_issues_count"`
+	LastActivityAt    time.Time `json:"last_activity_at"`
+	Topics            []string  `json:"topics"`
+}
+
+type gitlabTag struct {
+	Name string `json:"name"`
+}
+
+type gitlabTreeEntry struct {
+	Path string `json:"path"`
+	Type string `json:"type"`
+}
+
+func newGitLabClient(token, apiBaseURL string, transport http.RoundTripper) *gitlabClient {
+	baseURL := gitlabAPIURL
+	if apiBaseURL != "" {
+		baseURL = strings.TrimSuffix(apiBaseURL, "/")
+	}
+	return &gitlabClient{
+		baseURL:    baseURL,
+		token:      token,
+		httpClient: &http.Client{Transport: &loggingTransport{base: transport}},
+	}
+}
+
+func (c *gitlabClient) ListRepos(ctx context.Context, owner string) ([]*Repository, error) {
+	return c.listProjects(ctx, "/groups/"+url.PathEscape(owner)+"/projects", owner)
+}
+
+func (c *gitlabClient) ListUserRepos(ctx context.Context, user string) ([]*Repository, error) {
+	return c.listProjects(ctx, "/users/"+url.PathEscape(user)+"/projects", user)
+}
+
+func (c *gitlabClient) listProjects(ctx context.Context, endpoint, owner string) ([]*Repository, error) {
+	var repos []*Repository
+	page := "1"
+	for page != "" {
+		query := url.Values{"per_page": {"100"}, "page": {page}}
+		var projects []gitlabProject
+		next, err := c.getJSON(ctx, endpoint, query, &projects)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range projects {
+			repos = append(repos, p.repository(owner))
+		}
+		page = next
+	}
+	return repos, nil
+}
+
+func (p gitlabProject) repository(owner string) *Repository {
+	return &Repository{
+		Provider:      "gitlab",
+		Owner:         owner,
+		Name:          p.Path,
+		Description:   p.Description,
+		HTMLURL:       p.WebURL,
+		CloneURL:      p.HTTPURLToRepo,
+		ReadmeURL:     p.ReadmeURL,
+		DefaultBranch: p.DefaultBranch,
+		Ref:           p.DefaultBranch,
+		Stars:         p.StarCount,
+		Forks:         p.ForksCount,
+		OpenIssues:    p.OpenIssuesCount,
+		UpdatedAt:     p.LastActivityAt,
+		PushedAt:      p.LastActivityAt,
+		Topics:        p.Topics,
+	}
+}
+
+func (c *gitlabClient) GetFileContent(ctx context.Context, repo *Repository, path string) (string, error) {
+	endpoint := fmt.Sprintf("/projects/%s/repository/files/%s/raw", projectID(repo), url.PathEscape(path))
+	resp, err := c.do(ctx, endpoint, url.Values{"ref": {repo.Ref}})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	return string(body), nil
+}
+
+func (c *gitlabClient) GetFileTree(ctx context.Context, repo *Repository) ([]TreeEntry, error) {
+	var entries []TreeEntry
+	page := "1"
+	for page != "" {
+		query := url.Values{
+			"ref":       {repo.Ref},
+			"recursive": {"true"},
+			"per_page":  {"100"},
+			"page":      {page},
+		}
+		var tree []gitlabTreeEntry
+		next, err := c.getJSON(ctx, "/projects/"+projectID(repo)+"/repository/tree", query, &tree)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range tree {
+			switch entry.Type {
+			case "blob":
+				entries = append(entries, TreeEntry{Path: entry.Path, Type: "file"})
+			case "tree":
+				entries = append(entries, TreeEntry{Path: entry.Path, Type: "dir"})
+			}
+		}
+		page = next
+	}
+	return entries, nil
+}
+
+func (c *gitlabClient) ListTags(ctx context.Context, repo *Repository) ([]string, error) {
+	var tags []string
+	page := "1"
+	for page != "" {
+		query := url.Values{"per_page": {"100"}, "page": {page}}
+		var batch []gitlabTag
+		next, err := c.getJSON(ctx, "/projects/"+projectID(repo)+"/repository/tags", query, &batch)
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range batch {
+			tags = append(tags, tag.Name)
+		}
+		page = next
+	}
+	return tags, nil
+}
+
+func (c *gitlabClient) DownloadArchive(ctx context.Context, repo *Repository) (io.ReadCloser, error) {
+	resp, err := c.do(ctx, "/projects/"+projectID(repo)+"/repository/archive.tar.gz", url.Values{"sha": {repo.Ref}})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// getJSON decodes the response of endpoint into v and returns the next page
+// number, or "" when there are no further pages.
+func (c *gitlabClient) getJSON(ctx context.Context, endpoint string, query url.Values, v any) (string, error) {
+	resp, err := c.do(ctx, endpoint, query)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return "", fmt.Errorf("failed to decode %s: %v", endpoint, err)
+	}
+	return resp.Header.Get("X-Next-Page"), nil
+}
+
+func (c *gitlabClient) do(ctx context.Context, endpoint string, query url.Values) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.token != "" {
+		req.Header.Set("PRIVATE-TOKEN", c.token)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("GET %s: unexpected status %s", endpoint, resp.Status)
+	}
+	return resp, nil
+}
+
+func projectID(repo *Repository) string {
+	return url.PathEscape(repo.Owner + "/" + repo.Name)
+}
@@ -0,0 +1,42 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"time"
+)
+
+const goReportCardURL = "https://goreportcard.com/report/"
+
+var goReportGradeRe = regexp.MustCompile(`<span class="label label-[^"]*">\s*([A-F][+-]?)\s*</span>`)
+
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// fetchGoReportGrade returns the Go Report Card grade of a module.
+func fetchGoReportGrade(ctx context.Context, importPath string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, goReportCardURL+importPath, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	match := goReportGradeRe.FindSubmatch(body)
+	if match == nil {
+		return "", fmt.Errorf("grade not found in report")
+	}
+	return string(match[1]), nil
+}
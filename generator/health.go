@@ -0,0 +1,40 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"runtime/debug"
+	"time"
+)
+
+type healthCheck struct {
+	GeneratedAt      time.Time `json:"generatedAt"`
+	PackageCount     int       `json:"packageCount"`
+	GeneratorVersion string    `json:"generatorVersion"`
+}
+
+const healthCheckName = "health.json"
+
+// generateHealthCheck writes health.json so monitoring can alert when the
+// site has not been regenerated recently.
+func generateHealthCheck(outputDir string, packageCount int) error {
+	data, err := json.MarshalIndent(healthCheck{
+		GeneratedAt:      time.Now().UTC(),
+		PackageCount:     packageCount,
+		GeneratorVersion: generatorVersion(),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode health check: %v", err)
+	}
+	return writeFileAtomic(filepath.Join(outputDir, healthCheckName), data)
+}
+
+// generatorVersion returns the module version of the running binary, or
+// "(devel)" when it was built from a checkout.
+func generatorVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "(devel)"
+}
@@ -0,0 +1,54 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"os/exec"
+)
+
+// runHook runs command with sh -c, logging its combined output line by line.
+func runHook(ctx context.Context, name, command string) error {
+	if command == "" {
+		return nil
+	}
+	log.Printf("Running %s hook: %s", name, command)
+	out := &lineLogger{prefix: "  [" + name + "] "}
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stdout = out
+	cmd.Stderr = out
+	err := cmd.Run()
+	out.flush()
+	if err != nil {
+		return fmt.Errorf("%s hook failed: %v", name, err)
+	}
+	return nil
+}
+
+// lineLogger is an io.Writer that logs every complete line written to it.
+type lineLogger struct {
+	prefix string
+	buf    []byte
+}
+
+func (l *lineLogger) Write(p []byte) (int, error) {
+	l.buf = append(l.buf, p...)
+	for {
+		i := bytes.IndexByte(l.buf, '\n')
+		if i < 0 {
+			break
+		}
+		log.Printf("%s%s", l.prefix, l.buf[:i])
+		l.buf = l.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// flush logs a trailing line without a newline.
+func (l *lineLogger) flush() {
+	if len(l.buf) > 0 {
+		log.Printf("%s%s", l.prefix, l.buf)
+		l.buf = nil
+	}
+}
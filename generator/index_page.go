@@ -0,0 +1,235 @@
+package generator
+
+import (
+	"html/template"
+	"path/filepath"
+	"strings"
+)
+
+// indexPage is the data of the index page template.
+type indexPage struct {
+	Domain   string
+	Name     string // organization name derived from Domain
+	Packages []PackageInfo
+}
+
+func generateIndexHTML(w *htmlWriter, packages []PackageInfo) error {
+	tmpl := template.Must(template.New("main-index").Funcs(template.FuncMap{
+		"join":          strings.Join,
+		"languageColor": languageColor,
+		"freshness":     freshnessClass,
+	}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="utf-8">
+    <title>{{.Domain}}</title>
+    <meta name="theme-color" content="#00ADD8">
+    <meta name="msapplication-config" content="/browserconfig.xml">
+    <link rel="manifest" href="/manifest.json">
+    <link rel="alternate" type="application/json" href="/packages.json">
+    <link rel="preconnect" href="https://github.com">
+    <link rel="dns-prefetch" href="https://pkg.go.dev">
+    <style>
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, Oxygen, Ubuntu, Cantarell, 'Open Sans', 'Helvetica Neue', sans-serif;
+            max-width: 800px;
+            margin: 0 auto;
+            padding: 2rem;
+            line-height: 1.6;
+        }
+        .package-list {
+            margin-top: 2rem;
+        }
+        .package-item {
+            margin-bottom: 1.5rem;
+            padding: 1rem;
+            border: 1px solid #eee;
+            border-radius: 4px;
+        }
+        .package-item h3 {
+            margin: 0 0 0.5rem 0;
+        }
+        .package-item p {
+            margin: 0.5rem 0;
+            color: #666;
+        }
+        .badge {
+            display: inline-block;
+            margin-right: 0.3rem;
+            padding: 0.1rem 0.5rem;
+            border-radius: 3px;
+            background: #eee;
+            color: #333;
+            font-size: 0.8em;
+            text-decoration: none;
+        }
+        .badge-warning {
+            background: #fff3cd;
+            color: #856404;
+        }
+        .badge-success {
+            background: #d4edda;
+            color: #155724;
+        }
+        .badge-danger {
+            background: #f8d7da;
+            color: #721c24;
+        }
+        .button {
+            display: inline-block;
+            padding: 0.3rem 0.8rem;
+            border-radius: 4px;
+            background: #00ADD8;
+            color: #fff;
+            text-decoration: none;
+        }
+        .release-notes {
+            white-space: pre-line;
+            font-size: 0.9em;
+        }
+        .language-dot {
+            display: inline-block;
+            width: 0.6rem;
+            height: 0.6rem;
+            margin-right: 0.3rem;
+            border-radius: 50%;
+        }
+        code {
+            background: #f5f5f5;
+            padding: 0.2rem 0.4rem;
+            border-radius: 3px;
+            font-size: 0.9em;
+        }
+        @media (prefers-color-scheme: dark) {
+            body {
+                background: #1a1a1a;
+                color: #e0e0e0;
+            }
+            a {
+                color: #6cb6ff;
+            }
+            .package-item {
+                border-color: #333;
+            }
+            .package-item p {
+                color: #aaa;
+            }
+            .badge {
+                background: #333;
+                color: #e0e0e0;
+            }
+            .badge-warning {
+                background: #4d3d00;
+                color: #ffd866;
+            }
+            .badge-success {
+                background: #1e4620;
+                color: #8fd19e;
+            }
+            .badge-danger {
+                background: #4d1a1f;
+                color: #f1aeb5;
+            }
+            code {
+                background: #2a2a2a;
+            }
+        }
+    </style>
+</head>
+<body>
+    <h1>{{.Domain}}</h1>
+    <p>This is the package index for {{.Name}} Go packages.</p>
+    <p>To use these packages in your Go project, simply import them using the <code>{{.Domain}}/...</code>
+        import path.</p>
+    
+    <div class="package-list">
+        <h2>Available Packages</h2>
+        {{range .Packages}}
+        <div class="package-item">
+            <h3><a href="{{.RepoURL}}">{{.ImportPath}}</a></h3>
+            {{if .Description}}
+            <p>{{.Description}}</p>
+            {{end}}
+            {{if not .IsModule}}
+            <p><strong>Deprecated:</strong> this repository is not a Go module.</p>
+            {{end}}
+            <div class="badges">
+                {{if .Vulnerabilities}}<span class="badge badge-warning" title="{{join .Vulnerabilities ", "}}">⚠ {{len .Vulnerabilities}} known vulnerabilities</span>{{end}}
+                {{if .PrimaryLanguage}}<span class="badge"><span class="language-dot" style="{{languageColor .PrimaryLanguage}}"></span>{{.PrimaryLanguage}}</span>{{end}}
+                {{if .CIBadgeURL}}<a href="{{.CIURL}}"><img src="{{.CIBadgeURL}}" alt="CI status"></a>{{end}}
+                {{if .CoverageURL}}<img src="{{.CoverageURL}}" alt="Coverage">{{end}}
+                {{if .HasDocCoverage}}<span class="badge">Docs {{printf "%.0f" .DocCoverage}}%</span>{{end}}
+                {{if .HasExamples}}<a class="badge" href="{{.GodocLink}}#pkg-examples">Examples available</a>{{end}}
+                {{if .HasReleaseData}}{{if lt .LastReleaseDaysAgo 0}}<span class="badge badge-danger">No releases</span>{{else}}<span class="badge {{freshness .LastReleaseDaysAgo}}" title="Released {{.LastReleaseDate}}">Released {{.LastReleaseDaysAgo}} days ago</span>{{end}}{{end}}
+                {{if .APIStability}}<span class="badge{{if eq .APIStability "Unstable"}} badge-warning{{end}}" title="Latest release {{.LatestTag}}">{{.APIStability}} API</span>{{end}}
+                {{if .IsCLI}}<span class="badge">CLI tool</span>{{end}}
+                {{if .TypesOnly}}<span class="badge">Interface definition</span>{{end}}
+                {{if .HasWASM}}<span class="badge">WASM compatible</span>{{end}}
+                {{if .HasGoEmbed}}<span class="badge">Embeds assets</span>{{end}}
+                {{if .ProtocolBuffers}}<span class="badge" title="Defines Protocol Buffer schemas">gRPC/Protobuf</span>{{end}}
+                {{if .HasDocker}}<span class="badge" title="Dockerfile available">🐳 Docker</span>{{end}}
+                {{if .HasChangelog}}<span class="badge">Changelog available</span>{{end}}
+                {{if .SBOMAssetURL}}<a class="badge" href="{{.SBOMAssetURL}}">SBOM available</a>{{end}}
+                {{if .GoReport}}<a class="badge" href="https://goreportcard.com/report/{{.ImportPath}}">Go Report {{.GoReport}}</a>{{end}}
+            </div>
+            <p>{{.Stars}} stars · {{.Forks}} forks · {{.Watchers}} watchers{{if .TotalDownloads}} · {{.TotalDownloads}} downloads{{end}}{{if .CommitsPerDay}} · {{printf "%.1f" .CommitsPerDay}} commits/day{{end}}{{if .MajorVersions}} · {{.MajorVersions}} major versions released{{end}}</p>
+            {{if .TestedGoVersions}}
+            <p>Tested on Go {{join .TestedGoVersions ", "}}</p>
+            {{end}}
+            {{if .ContributorCount}}
+            <p>{{.ContributorCount}} contributors</p>
+            {{end}}
+            <p><code>{{.InstallCommand}}</code></p>
+            {{if .BinaryName}}
+            <p><code>go install {{.ImportPath}}/cmd/{{.BinaryName}}@latest</code></p>
+            {{end}}
+            <p><a class="button" href="{{.GodocLink}}">View documentation</a></p>
+            {{if .HasGenerators}}
+            <p><strong>Note:</strong> this package uses <code>go generate</code>; regenerating code may require additional tools.</p>
+            {{end}}
+            {{if .DirectDeps}}
+            <p>Depends on:
+                {{range $i, $dep := .DirectDeps}}{{if lt $i 5}}<code>{{$dep}}</code> {{end}}{{end}}
+            </p>
+            {{if gt (len .DirectDeps) 5}}
+            <details>
+                <summary>Show all {{len .DirectDeps}} dependencies</summary>
+                <ul>
+                    {{range .DirectDeps}}
+                    <li><code>{{.}}</code></li>
+                    {{end}}
+                </ul>
+            </details>
+            {{end}}
+            {{end}}
+            {{if .ChangelogEntries}}
+            <details>
+                <summary>Recent changes</summary>
+                {{range .ChangelogEntries}}
+                <h4>{{.Tag}} <small>{{.Date}}</small></h4>
+                <p class="release-notes">{{.Body}}</p>
+                {{end}}
+            </details>
+            {{end}}
+            {{if .SubPackages}}
+            <details>
+                <summary>{{len .SubPackages}} subpackage(s)</summary>
+                <ul>
+                    {{range .SubPackages}}
+                    <li><code>{{.}}</code></li>
+                    {{end}}
+                </ul>
+            </details>
+            {{end}}
+        </div>
+        {{end}}
+    </div>
+</body>
+</html>`))
+
+	return w.writeTemplate(filepath.Join(w.outputDir, "index.html"), tmpl, indexPage{
+		Domain:   w.baseDomain,
+		Name:     shortName(w.baseDomain),
+		Packages: packages,
+	})
+}
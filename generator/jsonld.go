@@ -0,0 +1,36 @@
+package generator
+
+import "encoding/json"
+
+// softwareSourceCode is the Schema.org SoftwareSourceCode structured data
+// embedded in package pages for search engines.
+type softwareSourceCode struct {
+	Context             string `json:"@context"`
+	Type                string `json:"@type"`
+	Name                string `json:"name"`
+	Description         string `json:"description,omitempty"`
+	URL                 string `json:"url"`
+	ProgrammingLanguage string `json:"programmingLanguage"`
+	License             string `json:"license,omitempty"`
+}
+
+// jsonLD returns the JSON-LD document describing pkg. encoding/json escapes
+// '<', '>' and '&', so the result is safe inside a <script> element.
+func jsonLD(pkg PackageInfo) (string, error) {
+	doc := softwareSourceCode{
+		Context:             "https://schema.org",
+		Type:                "SoftwareSourceCode",
+		Name:                pkg.ImportPath,
+		Description:         pkg.Description,
+		URL:                 pkg.RepoURL,
+		ProgrammingLanguage: "Go",
+	}
+	if pkg.License != "" && pkg.License != "NOASSERTION" {
+		doc.License = "https://spdx.org/licenses/" + pkg.License
+	}
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
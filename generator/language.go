@@ -0,0 +1,26 @@
+package generator
+
+import "html/template"
+
+// languageColors follows the colors GitHub uses for its language bar.
+var languageColors = map[string]string{
+	"Go":         "#00ADD8",
+	"JavaScript": "#f1e05a",
+	"TypeScript": "#3178c6",
+	"Rust":       "#dea584",
+	"Python":     "#3572A5",
+	"Shell":      "#89e051",
+	"C":          "#555555",
+	"C++":        "#f34b7d",
+	"Java":       "#b07219",
+	"HTML":       "#e34c26",
+}
+
+// languageColor returns the CSS declaration coloring the badge of language.
+func languageColor(language string) template.CSS {
+	color, ok := languageColors[language]
+	if !ok {
+		color = "#cccccc"
+	}
+	return template.CSS("background-color: " + color)
+}
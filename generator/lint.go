@@ -0,0 +1,69 @@
+package generator
+
+import (
+	"fmt"
+	"html"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var goImportRe = regexp.MustCompile(`<meta\s+name="go-import"\s+content="([^"]*)"`)
+
+// vcsTypes are the VCS names the go command accepts in a go-import tag.
+var vcsTypes = map[string]bool{
+	"bzr":    true,
+	"fossil": true,
+	"git":    true,
+	"hg":     true,
+	"mod":    true,
+	"svn":    true,
+}
+
+// LintProblem is an invalid go-import tag found by LintSite.
+type LintProblem struct {
+	File    string
+	Message string
+}
+
+// LintSite checks the go-import tags of every index.html below dir.
+func LintSite(dir string) ([]LintProblem, error) {
+	var problems []LintProblem
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || d.Name() != "index.html" {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, match := range goImportRe.FindAllSubmatch(content, -1) {
+			if msg := lintGoImport(html.UnescapeString(string(match[1]))); msg != "" {
+				problems = append(problems, LintProblem{File: path, Message: msg})
+			}
+		}
+		return nil
+	})
+	return problems, err
+}
+
+// lintGoImport validates the content of a go-import meta tag and returns a
+// description of the problem, or "" if it is valid.
+func lintGoImport(content string) string {
+	fields := strings.Fields(content)
+	if len(fields) != 3 {
+		return fmt.Sprintf("go-import has %d fields, want 3: %q", len(fields), content)
+	}
+	if !vcsTypes[fields[1]] {
+		return fmt.Sprintf("go-import has unrecognized VCS type %q", fields[1])
+	}
+	if fields[0] != strings.ToLower(fields[0]) {
+		return fmt.Sprintf("go-import path %q contains uppercase letters", fields[0])
+	}
+	return ""
+}
@@ -0,0 +1,120 @@
+package generator
+
+import (
+	"html/template"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// listingEntry is a package shown on a directory listing.
+type listingEntry struct {
+	ImportPath  string
+	Description string
+}
+
+// listing is the page of an intermediate path, e.g. pkg.blksails.net/foo
+// when only pkg.blksails.net/foo/bar/baz is a package.
+type listing struct {
+	Path     string
+	Packages []listingEntry
+}
+
+// pagePaths returns the import paths of the pages generated for packages,
+// mapped to the description shown for them.
+func pagePaths(packages []PackageInfo) map[string]string {
+	pages := make(map[string]string)
+	for _, pkg := range packages {
+		pages[pkg.RepoImportPath] = pkg.Description
+		pages[pkg.ImportPath] = pkg.Description
+		for _, sub := range pkg.SubPackages {
+			pages[sub] = pkg.Description
+		}
+	}
+	return pages
+}
+
+// intermediateListings returns a listing for every path between the domain
+// and a package page that has no page itself, listing the listed packages
+// below it.
+func intermediateListings(packages, listed []PackageInfo, domain string) []listing {
+	pages := pagePaths(packages)
+	children := make(map[string][]listingEntry)
+	for importPath, description := range pagePaths(listed) {
+		for dir := path.Dir(importPath); strings.HasPrefix(dir, domain+"/"); dir = path.Dir(dir) {
+			if _, ok := pages[dir]; !ok {
+				children[dir] = append(children[dir], listingEntry{importPath, description})
+			}
+		}
+	}
+
+	listings := make([]listing, 0, len(children))
+	for dir, entries := range children {
+		sort.Slice(entries, func(i, j int) bool { return entries[i].ImportPath < entries[j].ImportPath })
+		listings = append(listings, listing{Path: dir, Packages: entries})
+	}
+	sort.Slice(listings, func(i, j int) bool { return listings[i].Path < listings[j].Path })
+	return listings
+}
+
+// generateListingPages writes an index.html into every intermediate
+// directory so that e.g. pkg.blksails.net/foo/ lists the packages below it
+// instead of returning 404. Only listed packages appear on the listings.
+func generateListingPages(w *htmlWriter, packages, listed []PackageInfo) (int, error) {
+	tmpl := template.Must(template.New("listing").Parse(`<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="utf-8">
+    <title>{{.Path}}</title>
+    <style>
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, Oxygen, Ubuntu, Cantarell, 'Open Sans', 'Helvetica Neue', sans-serif;
+            max-width: 800px;
+            margin: 0 auto;
+            padding: 2rem;
+            line-height: 1.6;
+        }
+        code {
+            background: #f5f5f5;
+            padding: 0.2rem 0.4rem;
+            border-radius: 3px;
+        }
+        @media (prefers-color-scheme: dark) {
+            body {
+                background: #1a1a1a;
+                color: #e0e0e0;
+            }
+            a {
+                color: #6cb6ff;
+            }
+            code {
+                background: #2a2a2a;
+            }
+        }
+    </style>
+</head>
+<body>
+    <h1>{{.Path}}/</h1>
+    <ul>
+        {{range .Packages}}
+        <li><a href="https://{{.ImportPath}}/"><code>{{.ImportPath}}</code></a>{{if .Description}} — {{.Description}}{{end}}</li>
+        {{end}}
+    </ul>
+    <p><a href="/">All packages</a></p>
+</body>
+</html>`))
+
+	listings := intermediateListings(packages, listed, w.baseDomain)
+	for _, l := range listings {
+		dirPath := filepath.Join(w.outputDir, strings.TrimPrefix(l.Path, w.baseDomain+"/"))
+		if err := os.MkdirAll(dirPath, 0755); err != nil {
+			return 0, err
+		}
+		if err := w.writeTemplate(filepath.Join(dirPath, "index.html"), tmpl, l); err != nil {
+			return 0, err
+		}
+	}
+	return len(listings), nil
+}
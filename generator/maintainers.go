@@ -0,0 +1,72 @@
+package generator
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"strings"
+)
+
+var emailRe = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+// maintainerEmail returns the first email address on the first non-empty
+// line of the repository's MAINTAINERS file.
+func maintainerEmail(ctx context.Context, client VCSClient, repo *Repository, files map[string]bool) string {
+	if !files["MAINTAINERS"] {
+		return ""
+	}
+	content, err := client.GetFileContent(ctx, repo, "MAINTAINERS")
+	if err != nil {
+		log.Printf("  Failed to read MAINTAINERS: %v", err)
+		return ""
+	}
+	for _, line := range strings.Split(content, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return emailRe.FindString(line)
+		}
+	}
+	return ""
+}
+
+// endorsements returns the names listed one per line in the repository's
+// ENDORSED_BY file, skipping blank lines.
+func endorsements(ctx context.Context, client VCSClient, repo *Repository, files map[string]bool) []string {
+	if !files["ENDORSED_BY"] {
+		return nil
+	}
+	content, err := client.GetFileContent(ctx, repo, "ENDORSED_BY")
+	if err != nil {
+		log.Printf("  Failed to read ENDORSED_BY: %v", err)
+		return nil
+	}
+	var names []string
+	for _, line := range strings.Split(content, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			names = append(names, line)
+		}
+	}
+	return names
+}
+
+// commentEmail returns the first email address found in a // comment of a
+// go.mod file, such as "// Author: Jane Doe <jane@example.com>".
+func commentEmail(gomod string) string {
+	for _, line := range strings.Split(gomod, "\n") {
+		if _, comment, ok := strings.Cut(line, "//"); ok {
+			if email := emailRe.FindString(comment); email != "" {
+				return email
+			}
+		}
+	}
+	return ""
+}
+
+// reverse returns s with its characters in reverse order. Package pages
+// store maintainer emails reversed so naive scrapers cannot harvest them.
+func reverse(s string) string {
+	r := []rune(s)
+	for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+		r[i], r[j] = r[j], r[i]
+	}
+	return string(r)
+}
@@ -0,0 +1,64 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const themeColor = "#00ADD8" // Go blue
+
+type webManifest struct {
+	Name            string `json:"name"`
+	ShortName       string `json:"short_name"`
+	StartURL        string `json:"start_url"`
+	Display         string `json:"display"`
+	ThemeColor      string `json:"theme_color"`
+	BackgroundColor string `json:"background_color"`
+}
+
+// generateWebManifest writes the PWA manifest.json and the browserconfig.xml
+// used by IE/Edge tiles.
+func generateWebManifest(domain, outputDir string) error {
+	manifest, err := json.MarshalIndent(webManifest{
+		Name:            domain,
+		ShortName:       shortName(domain),
+		StartURL:        "/",
+		Display:         "standalone",
+		ThemeColor:      themeColor,
+		BackgroundColor: "#ffffff",
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, "manifest.json"), manifest, 0644); err != nil {
+		return fmt.Errorf("failed to create manifest file: %v", err)
+	}
+
+	browserConfig := fmt.Sprintf(`<?xml version="1.0" encoding="utf-8"?>
+<browserconfig>
+    <msapplication>
+        <tile>
+            <TileColor>%s</TileColor>
+        </tile>
+    </msapplication>
+</browserconfig>
+`, themeColor)
+	if err := os.WriteFile(filepath.Join(outputDir, "browserconfig.xml"), []byte(browserConfig), 0644); err != nil {
+		return fmt.Errorf("failed to create browserconfig file: %v", err)
+	}
+
+	return nil
+}
+
+// shortName returns the second-level label of domain, e.g. "blksails" for
+// pkg.blksails.net.
+func shortName(domain string) string {
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return domain
+	}
+	return labels[len(labels)-2]
+}
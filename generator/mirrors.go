@@ -0,0 +1,29 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// loadMirrors reads a YAML file mapping import paths to mirror repository
+// URLs keyed by hosting provider:
+//
+//	pkg.blksails.net/foo:
+//	  gitlab: https://gitlab.com/blksails/foo
+//	  bitbucket: https://bitbucket.org/blksails/foo
+func loadMirrors(name string) (map[string]map[string]string, error) {
+	if name == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mirrors file: %v", err)
+	}
+	var mirrors map[string]map[string]string
+	if err := yaml.Unmarshal(data, &mirrors); err != nil {
+		return nil, fmt.Errorf("failed to parse mirrors file %s: %v", name, err)
+	}
+	return mirrors, nil
+}
@@ -0,0 +1,49 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"path/filepath"
+	"text/template"
+)
+
+const ogImageName = "og-image.svg"
+
+// writeOGImage writes the social preview image of pkg to dirPath: the import
+// path in large text, the description below it and the blksails mark.
+func writeOGImage(dirPath string, pkg PackageInfo) error {
+	tmpl := template.Must(template.New("og-image").Funcs(template.FuncMap{
+		"escape":   html.EscapeString,
+		"truncate": truncate,
+	}).Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="1200" height="630" viewBox="0 0 1200 630">
+    <rect width="1200" height="630" fill="#ffffff"/>
+    <rect width="1200" height="12" fill="#00ADD8"/>
+    <g transform="translate(80 90)">
+        <rect width="72" height="72" rx="16" fill="#00ADD8"/>
+        <text x="36" y="50" font-family="sans-serif" font-size="40" font-weight="bold" fill="#ffffff" text-anchor="middle">b</text>
+        <text x="96" y="48" font-family="sans-serif" font-size="32" fill="#555555">blksails</text>
+    </g>
+    <text x="80" y="330" font-family="monospace" font-size="52" font-weight="bold" fill="#1a1a1a">{{ escape (truncate .ImportPath 40) }}</text>
+    {{- if .Description }}
+    <text x="80" y="410" font-family="sans-serif" font-size="30" fill="#555555">{{ escape (truncate .Description 70) }}</text>
+    {{- end }}
+</svg>
+`))
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, pkg); err != nil {
+		return fmt.Errorf("failed to execute og image template: %v", err)
+	}
+	return writeFileAtomic(filepath.Join(dirPath, ogImageName), buf.Bytes())
+}
+
+// truncate shortens s to at most n characters, ending it with an ellipsis
+// when it was cut.
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
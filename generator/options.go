@@ -0,0 +1,89 @@
+package generator
+
+import "context"
+
+// Option customizes a Config, either through New or as an argument of Run.
+type Option func(*Config)
+
+// Generator runs the generator as a library, configured with options
+// instead of command line flags.
+type Generator struct {
+	cfg Config
+}
+
+// New returns a Generator with the command line defaults — GitHub as the
+// provider, "public" as the output directory and one page writer — changed
+// by opts.
+func New(opts ...Option) *Generator {
+	g := &Generator{cfg: Config{
+		OutputDir:   "public",
+		VCSProvider: "github",
+		ParallelIO:  1,
+	}}
+	for _, opt := range opts {
+		opt(&g.cfg)
+	}
+	return g
+}
+
+// Config returns the configuration g runs with.
+func (g *Generator) Config() *Config {
+	return &g.cfg
+}
+
+// Run validates the configuration and generates the site.
+func (g *Generator) Run(ctx context.Context) (Stats, error) {
+	if err := g.cfg.Validate(); err != nil {
+		return Stats{}, err
+	}
+	return Run(ctx, &g.cfg)
+}
+
+// WithOrgName indexes the repositories of a single organization.
+func WithOrgName(name string) Option {
+	return func(c *Config) {
+		c.Orgs = []string{name}
+	}
+}
+
+// WithBaseDomain sets the vanity import domain of the indexed modules.
+func WithBaseDomain(domain string) Option {
+	return func(c *Config) {
+		c.BaseDomain = domain
+	}
+}
+
+// WithOutputDir sets the directory the site is written to.
+func WithOutputDir(dir string) Option {
+	return func(c *Config) {
+		c.OutputDir = dir
+	}
+}
+
+// WithConcurrency sets the number of goroutines running packages through the
+// pipeline.
+func WithConcurrency(n int) Option {
+	return func(c *Config) {
+		c.ParallelIO = n
+	}
+}
+
+// Middleware rewrites a package after its data has been fetched and before
+// its pages are generated, e.g. to enrich it from an internal database. It
+// runs in the enrich stage of the pipeline.
+type Middleware func(PackageInfo) PackageInfo
+
+// WithMiddleware appends middlewares, which run in the given order.
+func WithMiddleware(m ...Middleware) Option {
+	return func(c *Config) {
+		c.Middleware = append(c.Middleware, m...)
+	}
+}
+
+// WithStage inserts a custom pipeline stage after the stage named after,
+// e.g. FetchStageName. Stages are inserted in the given order.
+func WithStage(after string, stage Stage) Option {
+	return func(c *Config) {
+		c.Stages = append(c.Stages, StageInsertion{After: after, Stage: stage})
+	}
+}
@@ -0,0 +1,63 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const osvQueryURL = "https://api.osv.dev/v1/query"
+
+type osvQuery struct {
+	Package osvPackage `json:"package"`
+	Version string     `json:"version"`
+}
+
+type osvPackage struct {
+	Name      string `json:"name"`
+	Ecosystem string `json:"ecosystem"`
+}
+
+type osvResponse struct {
+	Vulns []struct {
+		ID string `json:"id"`
+	} `json:"vulns"`
+}
+
+// queryVulnerabilities returns the IDs of the OSV.dev vulnerabilities
+// affecting version of module.
+func queryVulnerabilities(ctx context.Context, module, version string) ([]string, error) {
+	body, err := json.Marshal(osvQuery{
+		Package: osvPackage{Name: module, Ecosystem: "Go"},
+		Version: strings.TrimPrefix(version, "v"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, osvQueryURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	var result osvResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+	var ids []string
+	for _, vuln := range result.Vulns {
+		ids = append(ids, vuln.ID)
+	}
+	return ids, nil
+}
@@ -0,0 +1,218 @@
+package generator
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// formatSize renders a size in KB, switching to MB with one decimal from
+// 1 MB on.
+func formatSize(kb int) string {
+	if kb < 1024 {
+		return fmt.Sprintf("%d KB", kb)
+	}
+	return fmt.Sprintf("%.1f MB", float64(kb)/1024)
+}
+
+// httpURL returns rawURL if it is an absolute http or https URL and ""
+// otherwise, so owner-controlled links such as the homepage cannot carry
+// javascript: or data: URLs into an href.
+func httpURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ""
+	}
+	return rawURL
+}
+
+// generateHTML renders the go-import page of a package. It uses text/template
+// so the meta tag contents are written verbatim apart from the explicit
+// escaping, keeping characters such as '+' intact for the go command.
+func generateHTML(w *htmlWriter, pkg PackageInfo) error {
+	relPath := strings.TrimPrefix(pkg.ImportPath, w.baseDomain+"/")
+	tmpl := template.Must(template.New("package").Funcs(template.FuncMap{
+		"escape":       html.EscapeString,
+		"deprecatedBy": deprecatedBy,
+		"reverse":      reverse,
+		"jsonLD":       jsonLD,
+		"ogImage": func() string {
+			if !w.ogImages {
+				return ""
+			}
+			return "https://" + w.baseDomain + "/" + relPath + "/" + ogImageName
+		},
+		"refresh": func() bool { return !w.noRefresh },
+		"httpURL": httpURL,
+		"size":    formatSize,
+	}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="utf-8">
+    <link rel="preconnect" href="https://github.com">
+    <link rel="dns-prefetch" href="https://pkg.go.dev">
+    <meta name="go-import" content="{{ escape .RepoImportPath }} git {{ escape .RepoURL }}">
+    <meta name="go-source" content="{{ escape .RepoImportPath }} {{ escape .RepoURL }} {{ escape .SourceDirURL }} {{ escape .SourceFileURL }}">
+    {{- if refresh }}
+    <meta http-equiv="refresh" content="0; url={{ escape .RepoURL }}">
+    {{- else }}
+    <script>window.location.replace("{{ js .RepoURL }}");</script>
+    {{- end }}
+    {{- with ogImage }}
+    <meta property="og:title" content="{{ escape $.ImportPath }}">
+    <meta property="og:image" content="{{ escape . }}">
+    {{- end }}
+    <style>
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, Oxygen, Ubuntu, Cantarell, 'Open Sans', 'Helvetica Neue', sans-serif;
+            line-height: 1.6;
+        }
+        code {
+            background: #f5f5f5;
+            padding: 0.2rem 0.4rem;
+            border-radius: 3px;
+        }
+        .badge {
+            display: inline-block;
+            padding: 0.1rem 0.5rem;
+            border-radius: 3px;
+            background: #eee;
+            color: #333;
+            font-size: 0.8em;
+        }
+        .badge-warning {
+            background: #fff3cd;
+            color: #856404;
+        }
+        .button {
+            display: inline-block;
+            padding: 0.3rem 0.8rem;
+            border-radius: 4px;
+            background: #00ADD8;
+            color: #fff;
+            text-decoration: none;
+        }
+        @media (prefers-color-scheme: dark) {
+            body {
+                background: #1a1a1a;
+                color: #e0e0e0;
+            }
+            a {
+                color: #6cb6ff;
+            }
+            code {
+                background: #2a2a2a;
+            }
+            .badge {
+                background: #333;
+                color: #e0e0e0;
+            }
+            .badge-warning {
+                background: #4d3d00;
+                color: #ffd866;
+            }
+        }
+    </style>
+    <script type="application/ld+json">{{ jsonLD . }}</script>
+</head>
+<body>
+    {{- if .IsInternal }}
+    <p><strong>Warning:</strong> this is an internal package; it is not supported for use outside the organization.</p>
+    {{- end }}
+    Redirecting to <a href="{{ escape (httpURL .RepoURL) }}">{{ escape .RepoURL }}</a>...
+    {{- if not .IsModule }}
+    <p><strong>Deprecated:</strong> this repository has no go.mod and is served under a legacy import path. Please migrate to Go modules.</p>
+    {{- end }}
+    {{- range .DeprecatedDeps }}
+    <p><strong>Deprecated dependency:</strong> <code>{{ escape . }}</code> has been superseded by <code>{{ escape (deprecatedBy .) }}</code>.</p>
+    {{- end }}
+    {{- if .CgoRequired }}
+    <p><span class="badge badge-warning">⚠ Requires cgo: building needs a C toolchain</span></p>
+    {{- end }}
+    <p><code>{{ escape .InstallCommand }}</code></p>
+    {{- if .ProxyStatus }}
+    <p><span class="badge">proxy.golang.org: {{ escape .ProxyStatus }}</span></p>
+    {{- end }}
+    {{- if .BinaryName }}
+    <p><code>go install {{ escape .ImportPath }}/cmd/{{ escape .BinaryName }}@latest</code></p>
+    {{- end }}
+    <p><a class="button" href="{{ escape .GodocLink }}">View documentation</a></p>
+    {{- with httpURL .ReadmeURL }}
+    <p><a href="{{ escape . }}">Read the docs</a></p>
+    {{- end }}
+    {{- with httpURL .HomepageURL }}
+    <p><a href="{{ escape . }}">Project homepage</a></p>
+    {{- end }}
+    {{- if .CodeSearchURL }}
+    <p><a href="{{ escape .CodeSearchURL }}">Search for usages</a></p>
+    {{- end }}
+    {{- with httpURL .ContributingURL }}
+    <p><a href="{{ escape . }}">How to contribute</a></p>
+    {{- end }}
+    {{- with httpURL .SecurityPolicyURL }}
+    <p><a href="{{ escape . }}">Security policy</a></p>
+    {{- end }}
+    {{- with httpURL .DiscussionURL }}
+    <p><a href="{{ escape . }}">Join discussions</a></p>
+    {{- end }}
+    {{- if .CloneURL }}
+    <p>
+        Clone: <code id="clone-url">{{ escape .CloneURL }}</code>
+        <button type="button" onclick="navigator.clipboard.writeText(document.getElementById('clone-url').textContent)">Copy</button>
+    </p>
+    {{- end }}
+    {{- if .MaintainerEmail }}
+    <p>Maintainer: <a id="maintainer" href="#" data-email="{{ escape (reverse .MaintainerEmail) }}">enable JavaScript to show the address</a></p>
+    <script>
+        (function () {
+            var a = document.getElementById('maintainer');
+            var email = a.dataset.email.split('').reverse().join('');
+            a.href = 'mailto:' + email;
+            a.textContent = email;
+        })();
+    </script>
+    {{- end }}
+    {{- if .Endorsements }}
+    <p>Endorsed by: {{ range $i, $name := .Endorsements }}{{ if $i }}, {{ end }}{{ escape $name }}{{ end }}</p>
+    {{- end }}
+    {{- if .EmbeddedFiles }}
+    <p>Bundled assets:</p>
+    <ul>
+        {{- range .EmbeddedFiles }}
+        <li><code>{{ escape . }}</code></li>
+        {{- end }}
+    </ul>
+    {{- end }}
+    {{- if .SecondaryRepoURLs }}
+    <p>Mirrors:
+        {{- range $provider, $url := .SecondaryRepoURLs }}
+        {{- with httpURL $url }}
+        <a href="{{ escape . }}">{{ escape $provider }}</a>
+        {{- end }}
+        {{- end }}
+    </p>
+    {{- end }}
+    {{- if .CloneSize }}
+    <p>Repository size: {{ size .CloneSize }}</p>
+    {{- end }}
+</body>
+</html>`))
+
+	// 创建目录结构
+	dirPath := filepath.Join(w.outputDir, relPath)
+	if err := os.MkdirAll(dirPath, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %v", err)
+	}
+	if w.ogImages {
+		if err := writeOGImage(dirPath, pkg); err != nil {
+			return err
+		}
+	}
+
+	// 创建 index.html 文件
+	return w.writeTemplate(filepath.Join(dirPath, "index.html"), tmpl, pkg)
+}
@@ -0,0 +1,66 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestGenerateHTMLKeepsPlusInGoImport(t *testing.T) {
+	dir := t.TempDir()
+	w := &htmlWriter{outputDir: dir, baseDomain: "pkg.blksails.net"}
+	pkg := PackageInfo{
+		ImportPath:     "pkg.blksails.net/a+b",
+		RepoImportPath: "pkg.blksails.net/a+b",
+		RepoURL:        "https://github.com/blksails/a+b",
+	}
+	if err := generateHTML(w, pkg); err != nil {
+		t.Fatalf("generateHTML: %v", err)
+	}
+
+	page, err := os.ReadFile(filepath.Join(dir, "a+b", "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := regexp.MustCompile(`<meta name="go-import" content="([^"]*)">`).FindSubmatch(page)
+	if m == nil {
+		t.Fatalf("no go-import meta tag in:\n%s", page)
+	}
+	if got, want := string(m[1]), "pkg.blksails.net/a+b git https://github.com/blksails/a+b"; got != want {
+		t.Errorf("go-import content = %q, want %q", got, want)
+	}
+}
+
+func TestGenerateHTMLWarnsAboutDeprecatedDeps(t *testing.T) {
+	dir := t.TempDir()
+	w := &htmlWriter{outputDir: dir, baseDomain: "pkg.blksails.net"}
+	sources := []sourceFile{{Path: "a.go", Content: "package a\n\nimport \"golang.org/x/exp/slices\"\n"}}
+	pkg := PackageInfo{
+		ImportPath:     "pkg.blksails.net/a",
+		RepoImportPath: "pkg.blksails.net/a",
+		RepoURL:        "https://github.com/blksails/a",
+		DirectDeps:     []string{"github.com/golang/protobuf", "golang.org/x/exp"},
+	}
+	pkg.DeprecatedDeps = deprecatedDeps(deprecatedDeps(nil, importPaths(sources)), pkg.DirectDeps)
+	if err := generateHTML(w, pkg); err != nil {
+		t.Fatalf("generateHTML: %v", err)
+	}
+
+	page, err := os.ReadFile(filepath.Join(dir, "a", "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"<code>github.com/golang/protobuf</code> has been superseded by <code>google.golang.org/protobuf</code>",
+		"<code>golang.org/x/exp/slices</code> has been superseded by <code>slices</code>",
+	} {
+		if !strings.Contains(string(page), want) {
+			t.Errorf("page does not contain %q:\n%s", want, page)
+		}
+	}
+	if strings.Contains(string(page), "<code>golang.org/x/exp</code> has been superseded") {
+		t.Errorf("golang.org/x/exp is reported as deprecated:\n%s", page)
+	}
+}
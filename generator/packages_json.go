@@ -0,0 +1,52 @@
+package generator
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// generatePackagesJSON writes packages.json, the machine-readable version of
+// the index page, together with a pre-compressed packages.json.gz.
+// Maintainer emails are left out: package pages only publish them obfuscated.
+func generatePackagesJSON(packages []PackageInfo, outputDir string) error {
+	public := make([]PackageInfo, len(packages))
+	for i, pkg := range packages {
+		pkg.MaintainerEmail = ""
+		public[i] = pkg
+	}
+	data, err := json.MarshalIndent(public, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode packages: %v", err)
+	}
+	path := filepath.Join(outputDir, "packages.json")
+	if err := writeFileAtomic(path, data); err != nil {
+		return err
+	}
+	return compressFile(path)
+}
+
+// compressFile writes a gzip-compressed copy of srcPath to srcPath.gz so a
+// CDN can serve it to clients sending Accept-Encoding: gzip.
+func compressFile(srcPath string) error {
+	data, err := os.ReadFile(srcPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", srcPath, err)
+	}
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+	zw.Name = filepath.Base(srcPath)
+	if _, err := zw.Write(data); err != nil {
+		return fmt.Errorf("failed to compress %s: %v", srcPath, err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to compress %s: %v", srcPath, err)
+	}
+	return writeFileAtomic(srcPath+".gz", buf.Bytes())
+}
@@ -0,0 +1,257 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+)
+
+// Stage is a step of the package pipeline. Process receives a package as
+// left by the previous stage and returns it, possibly modified.
+type Stage interface {
+	Name() string
+	Process(ctx context.Context, pkg PackageInfo) (PackageInfo, error)
+}
+
+// Names of the built-in stages, usable with Pipeline.InsertAfter.
+const (
+	FetchStageName    = "fetch"
+	EnrichStageName   = "enrich"
+	GenerateStageName = "generate"
+)
+
+// Pipeline runs every collected package through its stages in order.
+// Packages are processed concurrently when the pipeline has several workers,
+// so stages must be safe for concurrent use.
+type Pipeline struct {
+	stages   []Stage
+	workers  int
+	strategy string
+}
+
+// NewPipeline returns a pipeline running stages in the given order, one
+// package at a time.
+func NewPipeline(stages ...Stage) *Pipeline {
+	return &Pipeline{stages: stages, workers: 1}
+}
+
+// InsertAfter inserts stage right after the stage named stageName.
+func (p *Pipeline) InsertAfter(stageName string, stage Stage) error {
+	i := slices.IndexFunc(p.stages, func(s Stage) bool { return s.Name() == stageName })
+	if i < 0 {
+		return fmt.Errorf("no pipeline stage named %q", stageName)
+	}
+	p.stages = slices.Insert(p.stages, i+1, stage)
+	return nil
+}
+
+// Process runs pkg through every stage, stopping at the first error.
+func (p *Pipeline) Process(ctx context.Context, pkg PackageInfo) (PackageInfo, error) {
+	for _, stage := range p.stages {
+		var err error
+		if pkg, err = stage.Process(ctx, pkg); err != nil {
+			return pkg, fmt.Errorf("%s stage: %v", stage.Name(), err)
+		}
+	}
+	return pkg, nil
+}
+
+// Run processes packages and returns, in their original order, those that
+// went through every stage; the others are logged and dropped.
+func (p *Pipeline) Run(ctx context.Context, packages []PackageInfo) []PackageInfo {
+	for _, stage := range p.stages {
+		if pre, ok := stage.(preparer); ok {
+			pre.prepare(packages)
+		}
+	}
+	processed := make([]PackageInfo, len(packages))
+	ok := make([]bool, len(packages))
+	indexes := make([]int, len(packages))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	runJobs(indexes, p.workers, p.strategy, func(i int) {
+		pkg, err := p.Process(ctx, packages[i])
+		if err != nil {
+			log.Printf("Error processing %s: %v", packages[i].ImportPath, err)
+			return
+		}
+		processed[i], ok[i] = pkg, true
+	})
+	kept := processed[:0]
+	for i, pkg := range processed {
+		if ok[i] {
+			kept = append(kept, pkg)
+		}
+	}
+	return kept
+}
+
+// preparer is implemented by stages that look at every package before Run
+// starts processing them, since workers may take packages in any order.
+type preparer interface {
+	prepare(packages []PackageInfo)
+}
+
+// StageInsertion is a custom stage to insert after the stage named After.
+type StageInsertion struct {
+	After string
+	Stage Stage
+}
+
+// newPipeline returns the pipeline of cfg: fetch and enrich, followed by
+// generate when w is not nil, with the custom stages of cfg inserted.
+func newPipeline(cfg *Config, w *htmlWriter) (*Pipeline, error) {
+	enrich, err := newEnrichStage(cfg)
+	if err != nil {
+		return nil, err
+	}
+	p := NewPipeline(FetchStage{}, enrich)
+	p.workers, p.strategy = max(cfg.ParallelIO, 1), cfg.ParallelismStrategy
+	if w != nil {
+		p.stages = append(p.stages, &GenerateStage{w: w})
+	}
+	for _, ins := range cfg.Stages {
+		if err := p.InsertAfter(ins.After, ins.Stage); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// FetchStage queries the services reporting on a module: Go Report Card,
+// the module proxy and the vulnerability database. Failures are logged and
+// leave the corresponding field empty.
+type FetchStage struct{}
+
+func (FetchStage) Name() string { return FetchStageName }
+
+func (FetchStage) Process(ctx context.Context, pkg PackageInfo) (PackageInfo, error) {
+	if grade, err := fetchGoReportGrade(ctx, pkg.ImportPath); err != nil {
+		log.Printf("  Failed to fetch Go Report Card grade for %s: %v", pkg.ImportPath, err)
+	} else {
+		pkg.GoReport = grade
+	}
+	if status, err := proxyStatus(ctx, pkg.ImportPath); err != nil {
+		log.Printf("  Failed to check module proxy for %s: %v", pkg.ImportPath, err)
+	} else {
+		pkg.ProxyStatus = status
+	}
+	// Without a release there is no version to check, and OSV.dev would
+	// report every vulnerability ever filed against the module.
+	if pkg.LatestTag != "" {
+		if vulns, err := queryVulnerabilities(ctx, pkg.ImportPath, pkg.LatestTag); err != nil {
+			log.Printf("  Failed to query vulnerabilities for %s: %v", pkg.ImportPath, err)
+		} else {
+			pkg.Vulnerabilities = vulns
+		}
+	}
+	return pkg, nil
+}
+
+// EnrichStage fills in the links derived from the import path and the age of
+// the newest release, then applies the middlewares and description redaction
+// of the configuration.
+type EnrichStage struct {
+	mirrors    map[string]map[string]string
+	middleware []Middleware
+	redact     bool
+}
+
+func newEnrichStage(cfg *Config) (*EnrichStage, error) {
+	mirrors, err := loadMirrors(cfg.MirrorsFile)
+	if err != nil {
+		return nil, err
+	}
+	return &EnrichStage{mirrors: mirrors, middleware: cfg.Middleware, redact: cfg.RedactDescriptions}, nil
+}
+
+func (*EnrichStage) Name() string { return EnrichStageName }
+
+func (s *EnrichStage) Process(ctx context.Context, pkg PackageInfo) (PackageInfo, error) {
+	pkg.GodocLink = godocURL + pkg.ImportPath
+	pkg.CodeSearchURL = codeSearchURL(pkg.ImportPath)
+	pkg.SecondaryRepoURLs = s.mirrors[pkg.ImportPath]
+	pkg.LastReleaseDaysAgo = daysSince(pkg.LastReleaseDate)
+	pkg.InstallCommand = installCommand(pkg)
+	for _, m := range s.middleware {
+		pkg = m(pkg)
+	}
+	if s.redact {
+		pkg.Description = ""
+	}
+	return pkg, nil
+}
+
+// GenerateStage writes the page of a package and of its subpackages, plus
+// the page of the repository root the first time one of its packages is
+// seen, so go-import verification of the root path succeeds.
+type GenerateStage struct {
+	w *htmlWriter
+
+	mu    sync.Mutex
+	roots map[string]bool // repository roots with a page
+}
+
+func (*GenerateStage) Name() string { return GenerateStageName }
+
+// prepare marks the repository roots that are packages themselves, so their
+// own page is not replaced by one derived from a sub-module processed first.
+func (s *GenerateStage) prepare(packages []PackageInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.roots == nil {
+		s.roots = make(map[string]bool)
+	}
+	for _, pkg := range packages {
+		if pkg.ImportPath == pkg.RepoImportPath {
+			s.roots[pkg.RepoImportPath] = true
+		}
+	}
+}
+
+func (s *GenerateStage) Process(ctx context.Context, pkg PackageInfo) (PackageInfo, error) {
+	var jobs []pageJob
+	s.mu.Lock()
+	if s.roots == nil {
+		s.roots = make(map[string]bool)
+	}
+	if pkg.ImportPath != pkg.RepoImportPath && !s.roots[pkg.RepoImportPath] {
+		rootPkg := pkg
+		rootPkg.ImportPath = pkg.RepoImportPath
+		rootPkg.GodocLink = godocURL + rootPkg.ImportPath
+		rootPkg.CodeSearchURL = codeSearchURL(rootPkg.ImportPath)
+		rootPkg.InstallCommand = installCommand(rootPkg)
+		jobs = append(jobs, pageJob{rootPkg, "repo root HTML"})
+	}
+	s.roots[pkg.RepoImportPath] = true
+	s.mu.Unlock()
+
+	jobs = append(jobs, pageJob{pkg, "HTML"})
+	for _, importPath := range pkg.SubPackages {
+		subPkgInfo := pkg
+		subPkgInfo.ImportPath = importPath
+		subPkgInfo.GodocLink = godocURL + importPath
+		subPkgInfo.CodeSearchURL = codeSearchURL(importPath)
+		subPkgInfo.IsCLI, subPkgInfo.BinaryName = false, ""
+		subPkgInfo.InstallCommand = installCommand(subPkgInfo)
+		jobs = append(jobs, pageJob{subPkgInfo, "subpackage HTML"})
+	}
+
+	for _, job := range jobs {
+		if err := generateHTML(s.w, job.pkg); err != nil {
+			log.Printf("  Error generating %s for %s: %v", job.kind, job.pkg.ImportPath, err)
+		} else {
+			log.Printf("  ✓ Generated %s for %s", job.kind, job.pkg.ImportPath)
+		}
+	}
+	return pkg, nil
+}
+
+// pageJob is a page written by GenerateStage.
+type pageJob struct {
+	pkg  PackageInfo
+	kind string // used in log messages
+}
@@ -0,0 +1,92 @@
+package generator
+
+import "sync"
+
+// Scheduling strategies of the pipeline worker pool.
+const (
+	// StrategyRoundRobin feeds every worker from one shared FIFO channel.
+	StrategyRoundRobin = "round-robin"
+	// StrategyWorkStealing gives each worker its own queue; idle workers
+	// steal from their neighbors.
+	StrategyWorkStealing = "work-stealing"
+)
+
+// runJobs calls do for every job on the given number of goroutines and
+// returns once all jobs are done.
+func runJobs[T any](jobs []T, workers int, strategy string, do func(T)) {
+	workers = max(workers, 1)
+	var wg sync.WaitGroup
+	switch strategy {
+	case StrategyWorkStealing:
+		queues := make([]*jobQueue[T], workers)
+		for i := range queues {
+			queues[i] = &jobQueue[T]{}
+		}
+		for i, job := range jobs {
+			queues[i%workers].jobs = append(queues[i%workers].jobs, job)
+		}
+		for i := range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					job, ok := queues[i].popBack()
+					for n := 1; !ok && n < workers; n++ {
+						job, ok = queues[(i+n)%workers].popFront()
+					}
+					if !ok {
+						return
+					}
+					do(job)
+				}
+			}()
+		}
+	default:
+		ch := make(chan T)
+		for range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for job := range ch {
+					do(job)
+				}
+			}()
+		}
+		for _, job := range jobs {
+			ch <- job
+		}
+		close(ch)
+	}
+	wg.Wait()
+}
+
+// jobQueue is a worker's local deque. The owner takes jobs from the back,
+// thieves from the front, so they rarely contend for the same job.
+type jobQueue[T any] struct {
+	mu   sync.Mutex
+	jobs []T
+}
+
+func (q *jobQueue[T]) popBack() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.jobs) == 0 {
+		var zero T
+		return zero, false
+	}
+	job := q.jobs[len(q.jobs)-1]
+	q.jobs = q.jobs[:len(q.jobs)-1]
+	return job, true
+}
+
+func (q *jobQueue[T]) popFront() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.jobs) == 0 {
+		var zero T
+		return zero, false
+	}
+	job := q.jobs[0]
+	q.jobs = q.jobs[1:]
+	return job, true
+}
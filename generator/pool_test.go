@@ -0,0 +1,34 @@
+package generator
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// BenchmarkRunJobs compares the scheduling strategies on jobs of uneven
+// cost: every workers-th job is 50 times slower than the others, the shape
+// of a few large repositories among many small ones.
+func BenchmarkRunJobs(b *testing.B) {
+	const workers = 4
+	jobs := make([]time.Duration, 256)
+	for i := range jobs {
+		jobs[i] = 20 * time.Microsecond
+		if i%workers == 0 {
+			jobs[i] *= 50
+		}
+	}
+	for _, strategy := range []string{StrategyRoundRobin, StrategyWorkStealing} {
+		b.Run(fmt.Sprintf("%s/workers=%d", strategy, workers), func(b *testing.B) {
+			for b.Loop() {
+				runJobs(jobs, workers, strategy, spin)
+			}
+		})
+	}
+}
+
+// spin keeps the CPU busy for d, standing in for rendering a page.
+func spin(d time.Duration) {
+	for start := time.Now(); time.Since(start) < d; {
+	}
+}
@@ -0,0 +1,39 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"golang.org/x/mod/module"
+)
+
+const goProxyURL = "https://proxy.golang.org/"
+
+// proxyStatus reports whether the Go module proxy serves the latest version
+// of a module: "available", "forbidden" or "not found".
+func proxyStatus(ctx context.Context, modulePath string) (string, error) {
+	escaped, err := module.EscapePath(modulePath)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, goProxyURL+escaped+"/@latest", nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return "available", nil
+	case http.StatusForbidden:
+		return "forbidden", nil
+	case http.StatusNotFound, http.StatusGone:
+		return "not found", nil
+	default:
+		return "", fmt.Errorf("unexpected status %s", resp.Status)
+	}
+}
@@ -0,0 +1,90 @@
+package generator
+
+import (
+	"strings"
+	"time"
+)
+
+// ChangelogEntry is the release note of a single release.
+type ChangelogEntry struct {
+	Tag  string
+	Date string // YYYY-MM-DD
+	Body string
+}
+
+// sbomAssetURL returns the download URL of the SBOM attached to the newest
+// release that has one.
+func sbomAssetURL(releases []Release) string {
+	for _, release := range releases {
+		for _, asset := range release.Assets {
+			if strings.HasSuffix(asset.Name, ".sbom.json") || asset.Name == "sbom.spdx.json" {
+				return asset.DownloadURL
+			}
+		}
+	}
+	return ""
+}
+
+// totalDownloads sums the download counts of every release asset.
+func totalDownloads(releases []Release) int {
+	total := 0
+	for _, release := range releases {
+		for _, asset := range release.Assets {
+			total += asset.DownloadCount
+		}
+	}
+	return total
+}
+
+// changelogEntries returns the notes of the n newest releases.
+func changelogEntries(releases []Release, n int) []ChangelogEntry {
+	var entries []ChangelogEntry
+	for _, release := range releases[:min(n, len(releases))] {
+		entries = append(entries, ChangelogEntry{
+			Tag:  release.Tag,
+			Date: release.PublishedAt.Format(time.DateOnly),
+			Body: strings.TrimSpace(release.Body),
+		})
+	}
+	return entries
+}
+
+// lastReleaseDate returns the YYYY-MM-DD date of the newest release, or ""
+// when there is no release.
+func lastReleaseDate(releases []Release) string {
+	var newest time.Time
+	for _, release := range releases {
+		if release.PublishedAt.After(newest) {
+			newest = release.PublishedAt
+		}
+	}
+	if newest.IsZero() {
+		return ""
+	}
+	return newest.Format(time.DateOnly)
+}
+
+// daysSince returns the number of days elapsed since a YYYY-MM-DD date, or
+// -1 when date is empty or invalid. It is computed at every run rather than
+// stored, since packages of unchanged repositories come from the state file.
+func daysSince(date string) int {
+	t, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return -1
+	}
+	return int(time.Since(t).Hours() / 24)
+}
+
+// freshnessClass returns the badge class of a package by the age of its
+// newest release: fresh under 30 days, aging up to 180, stale beyond that
+// or without any release.
+func freshnessClass(daysAgo int) string {
+	switch {
+	case daysAgo < 0 || daysAgo > 180:
+		return "badge-danger"
+	case daysAgo >= 30:
+		return "badge-warning"
+	default:
+		return "badge-success"
+	}
+}
@@ -0,0 +1,38 @@
+package generator
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// WriteReport writes the metadata of packages as CSV.
+func WriteReport(w io.Writer, packages []PackageInfo) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"ImportPath", "RepoURL", "Description", "Stars", "OpenIssues", "UpdatedAt", "LatestTag", "License"}); err != nil {
+		return fmt.Errorf("failed to write header: %v", err)
+	}
+	for _, pkg := range packages {
+		updatedAt := ""
+		if !pkg.UpdatedAt.IsZero() {
+			updatedAt = pkg.UpdatedAt.Format(time.RFC3339)
+		}
+		record := []string{
+			pkg.ImportPath,
+			pkg.RepoURL,
+			pkg.Description,
+			strconv.Itoa(pkg.Stars),
+			strconv.Itoa(pkg.OpenIssues),
+			updatedAt,
+			pkg.LatestTag,
+			pkg.License,
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("failed to write %s: %v", pkg.ImportPath, err)
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,84 @@
+package generator
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+	"time"
+)
+
+const (
+	sitemapNS      = "http://www.sitemaps.org/schemas/sitemap/0.9"
+	sitemapMaxURLs = 50000 // limit of the sitemap protocol per file
+)
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type urlSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	NS      string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapRef struct {
+	Loc string `xml:"loc"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name     `xml:"sitemapindex"`
+	NS       string       `xml:"xmlns,attr"`
+	Sitemaps []sitemapRef `xml:"sitemap"`
+}
+
+// generateSitemap writes the sitemap of the index page and every package
+// page through generateSitemapIndex.
+func generateSitemap(packages []PackageInfo, domain, outputDir string) error {
+	urls := []sitemapURL{{Loc: "https://" + domain + "/"}}
+	seen := make(map[string]bool)
+	add := func(importPath string, updated time.Time) {
+		if seen[importPath] {
+			return
+		}
+		seen[importPath] = true
+		u := sitemapURL{Loc: "https://" + importPath + "/"}
+		if !updated.IsZero() {
+			u.LastMod = updated.Format(time.DateOnly)
+		}
+		urls = append(urls, u)
+	}
+	for _, pkg := range packages {
+		add(pkg.RepoImportPath, pkg.UpdatedAt)
+		add(pkg.ImportPath, pkg.UpdatedAt)
+		for _, sub := range pkg.SubPackages {
+			add(sub, pkg.UpdatedAt)
+		}
+	}
+	return generateSitemapIndex(urls, domain, outputDir)
+}
+
+// generateSitemapIndex splits urls into sitemap-1.xml, sitemap-2.xml, ... of
+// at most sitemapMaxURLs entries each and writes sitemap_index.xml
+// referencing all of them.
+func generateSitemapIndex(urls []sitemapURL, domain, outputDir string) error {
+	index := sitemapIndex{NS: sitemapNS}
+	for i := 0; i < len(urls); i += sitemapMaxURLs {
+		name := fmt.Sprintf("sitemap-%d.xml", len(index.Sitemaps)+1)
+		set := urlSet{NS: sitemapNS, URLs: urls[i:min(i+sitemapMaxURLs, len(urls))]}
+		if err := writeXML(filepath.Join(outputDir, name), set); err != nil {
+			return err
+		}
+		index.Sitemaps = append(index.Sitemaps, sitemapRef{Loc: "https://" + domain + "/" + name})
+	}
+	return writeXML(filepath.Join(outputDir, "sitemap_index.xml"), index)
+}
+
+func writeXML(path string, v any) error {
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %v", filepath.Base(path), err)
+	}
+	return writeFileAtomic(path, append([]byte(xml.Header), append(data, '\n')...))
+}
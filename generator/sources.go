@@ -0,0 +1,359 @@
+package generator
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"go/ast"
+	"go/build/constraint"
+	"go/parser"
+	"go/token"
+	"io"
+	"log"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// maxSourceFiles bounds the number of Go files fetched one by one from
+// clients that cannot download archives, as each one costs an API call.
+const maxSourceFiles = 200
+
+// maxSourceBytes bounds the Go sources read from a repository archive.
+const maxSourceBytes = 64 << 20
+
+// sourceFile is a Go file fetched for content scanning.
+type sourceFile struct {
+	Path    string
+	Content string
+}
+
+// fetchSources returns the Go files of the repository, skipping directories
+// ignored by the go tool. Clients that can download archives fetch the whole
+// repository in one request; others fall back to one request per file.
+func fetchSources(ctx context.Context, client VCSClient, repo *Repository, tree []TreeEntry) []sourceFile {
+	if downloader, ok := client.(archiveDownloader); ok {
+		sources, err := archiveSources(ctx, downloader, repo)
+		if err != nil {
+			log.Printf("  Failed to download the archive of %s: %v", repo.Name, err)
+		}
+		return sources
+	}
+	var sources []sourceFile
+	for _, entry := range tree {
+		if entry.Type != "file" || !strings.HasSuffix(entry.Path, ".go") || ignoredDir(path.Dir(entry.Path), nil) {
+			continue
+		}
+		if len(sources) == maxSourceFiles {
+			log.Printf("  Only scanning the first %d Go files of %s", maxSourceFiles, repo.Name)
+			break
+		}
+		content, err := client.GetFileContent(ctx, repo, entry.Path)
+		if err != nil {
+			log.Printf("  Failed to read %s: %v", entry.Path, err)
+			continue
+		}
+		sources = append(sources, sourceFile{Path: entry.Path, Content: content})
+	}
+	return sources
+}
+
+// archiveSources reads the Go files out of the tarball of repo.Ref. Paths
+// are made relative to the repository root by dropping the top directory of
+// the archive.
+func archiveSources(ctx context.Context, downloader archiveDownloader, repo *Repository) ([]sourceFile, error) {
+	body, err := downloader.DownloadArchive(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	gz, err := gzip.NewReader(body)
+	if err != nil {
+		return nil, err
+	}
+	var sources []sourceFile
+	total := 0
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return sources, nil
+		}
+		if err != nil {
+			return sources, err
+		}
+		_, name, ok := strings.Cut(hdr.Name, "/")
+		if !ok || hdr.Typeflag != tar.TypeReg || !strings.HasSuffix(name, ".go") || ignoredDir(path.Dir(name), nil) {
+			continue
+		}
+		if total += int(hdr.Size); total > maxSourceBytes {
+			log.Printf("  Only scanning the first %d MB of Go files of %s", maxSourceBytes>>20, repo.Name)
+			return sources, nil
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return sources, err
+		}
+		sources = append(sources, sourceFile{Path: name, Content: string(content)})
+	}
+}
+
+// isCLI reports whether the Go files in the repository root belong to
+// package main. It looks at the first non-test root file, reading it unless
+// fetchSources already did.
+func isCLI(ctx context.Context, client VCSClient, repo *Repository, tree []TreeEntry, sources []sourceFile) bool {
+	for _, entry := range tree {
+		if entry.Type != "file" || strings.Contains(entry.Path, "/") ||
+			!strings.HasSuffix(entry.Path, ".go") || strings.HasSuffix(entry.Path, "_test.go") {
+			continue
+		}
+		content := ""
+		if i := slices.IndexFunc(sources, func(src sourceFile) bool { return src.Path == entry.Path }); i >= 0 {
+			content = sources[i].Content
+		} else {
+			var err error
+			if content, err = client.GetFileContent(ctx, repo, entry.Path); err != nil {
+				log.Printf("  Failed to read %s: %v", entry.Path, err)
+				return false
+			}
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), entry.Path, content, parser.PackageClauseOnly)
+		return err == nil && file.Name.Name == "main"
+	}
+	return false
+}
+
+// hasDirective reports whether any source file contains a line starting
+// with the given comment directive, e.g. "//go:generate".
+func hasDirective(sources []sourceFile, directive string) bool {
+	for _, src := range sources {
+		for _, line := range strings.Split(src.Content, "\n") {
+			if strings.HasPrefix(strings.TrimSpace(line), directive) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasExamples reports whether any test file declares an example function.
+func hasExamples(sources []sourceFile) bool {
+	for _, src := range sources {
+		if !strings.HasSuffix(src.Path, "_test.go") {
+			continue
+		}
+		for _, line := range strings.Split(src.Content, "\n") {
+			if strings.HasPrefix(line, "func Example") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// docCoverage returns the percentage of exported identifiers declared in
+// non-test sources that have a doc comment, and false when there are none.
+func docCoverage(sources []sourceFile) (float64, bool) {
+	total, documented := 0, 0
+	count := func(name *ast.Ident, doc *ast.CommentGroup) {
+		if !name.IsExported() {
+			return
+		}
+		total++
+		if doc != nil {
+			documented++
+		}
+	}
+
+	fset := token.NewFileSet()
+	for _, src := range sources {
+		if strings.HasSuffix(src.Path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, src.Path, src.Content, parser.ParseComments)
+		if err != nil {
+			continue
+		}
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				count(decl.Name, decl.Doc)
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					switch spec := spec.(type) {
+					case *ast.TypeSpec:
+						count(spec.Name, firstDoc(spec.Doc, decl.Doc))
+					case *ast.ValueSpec:
+						for _, name := range spec.Names {
+							count(name, firstDoc(spec.Doc, decl.Doc))
+						}
+					}
+				}
+			}
+		}
+	}
+	if total == 0 {
+		return 0, false
+	}
+	return float64(documented) / float64(total) * 100, true
+}
+
+// typesOnly reports whether the non-test sources declare types, constants
+// and variables but no function with a body.
+func typesOnly(sources []sourceFile) bool {
+	fset := token.NewFileSet()
+	parsed := 0
+	for _, src := range sources {
+		if strings.HasSuffix(src.Path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, src.Path, src.Content, parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+		parsed++
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Body != nil {
+				return false
+			}
+		}
+	}
+	return parsed > 0
+}
+
+// importsC reports whether a non-test source imports the "C" pseudo-package.
+func importsC(sources []sourceFile) bool {
+	fset := token.NewFileSet()
+	for _, src := range sources {
+		if strings.HasSuffix(src.Path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, src.Path, src.Content, parser.ImportsOnly)
+		if err != nil {
+			continue
+		}
+		for _, imp := range file.Imports {
+			if imp.Path.Value == `"C"` {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// importPaths returns the distinct packages imported by the non-test sources.
+func importPaths(sources []sourceFile) []string {
+	var paths []string
+	fset := token.NewFileSet()
+	for _, src := range sources {
+		if strings.HasSuffix(src.Path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, src.Path, src.Content, parser.ImportsOnly)
+		if err != nil {
+			continue
+		}
+		for _, imp := range file.Imports {
+			if p, err := strconv.Unquote(imp.Path.Value); err == nil && !slices.Contains(paths, p) {
+				paths = append(paths, p)
+			}
+		}
+	}
+	return paths
+}
+
+// wasmTargets are the GOOS values WebAssembly builds use with GOARCH=wasm.
+var wasmTargets = []string{"js", "wasip1"}
+
+// nativeTargets are GOOS/GOARCH pairs a constraint must exclude to be
+// WebAssembly specific.
+var nativeTargets = [][2]string{{"linux", "amd64"}, {"darwin", "arm64"}, {"windows", "amd64"}}
+
+// hasWASM reports whether a source targets WebAssembly, either through a
+// _wasm.go file name suffix or a //go:build line that is satisfied by a
+// js/wasm or wasip1/wasm build but by none of nativeTargets.
+func hasWASM(sources []sourceFile) bool {
+	for _, src := range sources {
+		if strings.HasSuffix(src.Path, "_wasm.go") {
+			return true
+		}
+		for _, line := range strings.Split(src.Content, "\n") {
+			line = strings.TrimSpace(line)
+			if line == "package" || strings.HasPrefix(line, "package ") {
+				break
+			}
+			if !constraint.IsGoBuild(line) {
+				continue
+			}
+			expr, err := constraint.Parse(line)
+			if err != nil {
+				continue
+			}
+			if wasmOnly(expr) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// wasmOnly reports whether expr holds for a WebAssembly target and for none
+// of nativeTargets.
+func wasmOnly(expr constraint.Expr) bool {
+	for _, target := range nativeTargets {
+		if expr.Eval(targetTags(target[0], target[1])) {
+			return false
+		}
+	}
+	for _, goos := range wasmTargets {
+		if expr.Eval(targetTags(goos, "wasm")) {
+			return true
+		}
+	}
+	return false
+}
+
+// targetTags returns the build tag predicate of a gc build for goos/goarch.
+func targetTags(goos, goarch string) func(string) bool {
+	return func(tag string) bool {
+		return tag == goos || tag == goarch || tag == "gc" || (tag == "unix" && (goos == "linux" || goos == "darwin"))
+	}
+}
+
+// embedPatterns returns the distinct patterns of the //go:embed directives
+// in the non-test sources, in order of appearance.
+func embedPatterns(sources []sourceFile) []string {
+	var patterns []string
+	seen := make(map[string]bool)
+	for _, src := range sources {
+		if strings.HasSuffix(src.Path, "_test.go") {
+			continue
+		}
+		for _, line := range strings.Split(src.Content, "\n") {
+			args, ok := strings.CutPrefix(strings.TrimSpace(line), "//go:embed ")
+			if !ok {
+				continue
+			}
+			for _, pattern := range strings.Fields(args) {
+				if unquoted, err := strconv.Unquote(pattern); err == nil {
+					pattern = unquoted
+				}
+				if !seen[pattern] {
+					seen[pattern] = true
+					patterns = append(patterns, pattern)
+				}
+			}
+		}
+	}
+	return patterns
+}
+
+func firstDoc(docs ...*ast.CommentGroup) *ast.CommentGroup {
+	for _, doc := range docs {
+		if doc != nil {
+			return doc
+		}
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package generator
+
+import "testing"
+
+func TestHasWASM(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		content string
+		want    bool
+	}{
+		{"js and wasm", "a.go", "//go:build js && wasm\n\npackage a\n", true},
+		{"wasm", "a.go", "//go:build wasm\n\npackage a\n", true},
+		{"wasip1", "a.go", "//go:build wasip1\n\npackage a\n", true},
+		{"not js", "a.go", "//go:build !js\n\npackage a\n", false},
+		{"not wasm", "a.go", "//go:build !wasm\n\npackage a\n", false},
+		{"not plan9 and not js", "a.go", "//go:build !plan9 && !js\n\npackage a\n", false},
+		{"js or linux", "a.go", "//go:build js || linux\n\npackage a\n", false},
+		{"no constraint", "a.go", "package a\n", false},
+		{"after package clause", "a.go", "package a\n\n//go:build wasm\n", false},
+		{"file name suffix", "a_wasm.go", "package a\n", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasWASM([]sourceFile{{Path: tt.path, Content: tt.content}}); got != tt.want {
+				t.Errorf("hasWASM(%q) = %v, want %v", tt.content, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+// generatorState is persisted between runs so repositories that have not been
+// pushed to since the previous run can be skipped.
+type generatorState struct {
+	Config string               `json:"config"` // stateFingerprint of the run that wrote the state
+	Repos  map[string]repoState `json:"repos"`  // keyed by repository URL
+}
+
+type repoState struct {
+	PushedAt time.Time     `json:"pushedAt"`
+	Packages []PackageInfo `json:"packages"`
+}
+
+// stateFingerprint hashes the options that change the packages collected
+// from a repository, so changing any of them invalidates the cache.
+func stateFingerprint(cfg *Config) string {
+	data, _ := json.Marshal([]any{
+		generatorVersion(),
+		cfg.VCSProvider,
+		cfg.APIBaseURL,
+		cfg.BaseDomain,
+		cfg.BaseBranch,
+		cfg.IncludeNonModule,
+		cfg.NoSubpackages,
+		cfg.SubpackageRegex,
+		cfg.TagPrefix,
+	})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// loadState reads the state file, returning an empty state if it does not
+// exist yet or was written with a different configuration.
+func loadState(path, fingerprint string) (*generatorState, error) {
+	state := &generatorState{Config: fingerprint, Repos: make(map[string]repoState)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %v", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to decode state file: %v", err)
+	}
+	if state.Config != fingerprint {
+		log.Printf("Configuration changed since the state file was written, processing every repository")
+		return &generatorState{Config: fingerprint, Repos: make(map[string]repoState)}, nil
+	}
+	if state.Repos == nil {
+		state.Repos = make(map[string]repoState)
+	}
+	return state, nil
+}
+
+func (s *generatorState) save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write state file: %v", err)
+	}
+	return nil
+}
+
+// unchanged reports whether repo has not been pushed to since it was
+// recorded. The cached packages are refreshed with the listing data of repo,
+// such as stars and the description, which change without a push.
+func (s *generatorState) unchanged(repo *Repository) ([]PackageInfo, bool) {
+	cached, ok := s.Repos[repo.HTMLURL]
+	if !ok || repo.PushedAt.IsZero() || !cached.PushedAt.Equal(repo.PushedAt) {
+		return nil, false
+	}
+	return s.cached(repo)
+}
+
+// cached returns the packages recorded for repo, whether or not it has been
+// pushed to since, refreshed with its listing data.
+func (s *generatorState) cached(repo *Repository) ([]PackageInfo, bool) {
+	cached, ok := s.Repos[repo.HTMLURL]
+	if !ok {
+		return nil, false
+	}
+	for i := range cached.Packages {
+		setRepositoryFields(&cached.Packages[i], repo)
+	}
+	return cached.Packages, true
+}
+
+// setRepositoryFields copies the fields read from the repository listing
+// into pkg. Watchers are kept when the listing does not report them.
+func setRepositoryFields(pkg *PackageInfo, repo *Repository) {
+	pkg.RepoURL = repo.HTMLURL
+	pkg.CloneURL = repo.CloneURL
+	pkg.SourceDirURL = repo.webURL("tree", "{/dir}")
+	pkg.SourceFileURL = repo.webURL("blob", "{/dir}/{file}#L{line}")
+	pkg.Description = repo.Description
+	pkg.PrimaryLanguage = repo.Language
+	pkg.Stars = repo.Stars
+	if repo.Watchers != 0 {
+		pkg.Watchers = repo.Watchers
+	}
+	pkg.Forks = repo.Forks
+	pkg.OpenIssues = repo.OpenIssues
+	pkg.UpdatedAt = repo.UpdatedAt
+	pkg.License = repo.License
+	pkg.CloneSize = repo.Size
+	pkg.IsInternal = isInternal(repo)
+	pkg.HomepageURL = repo.Homepage
+}
+
+func (s *generatorState) record(repo *Repository, packages []PackageInfo) {
+	s.Repos[repo.HTMLURL] = repoState{PushedAt: repo.PushedAt, Packages: packages}
+}
@@ -0,0 +1,53 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+type tocEntry struct {
+	ImportPath  string `json:"importPath"`
+	HTMLPath    string `json:"htmlPath"`
+	Description string `json:"description"`
+}
+
+// generateTOC writes toc.json, listing every generated package page for
+// search crawlers.
+func generateTOC(packages []PackageInfo, outputDir string) error {
+	entries := []tocEntry{}
+	seen := make(map[string]bool)
+	add := func(importPath, description string) {
+		if seen[importPath] {
+			return
+		}
+		seen[importPath] = true
+		entries = append(entries, tocEntry{
+			ImportPath:  importPath,
+			HTMLPath:    htmlPath(importPath),
+			Description: description,
+		})
+	}
+	for _, pkg := range packages {
+		add(pkg.RepoImportPath, pkg.Description)
+		add(pkg.ImportPath, pkg.Description)
+		for _, sub := range pkg.SubPackages {
+			add(sub, pkg.Description)
+		}
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode toc: %v", err)
+	}
+	return writeFileAtomic(filepath.Join(outputDir, "toc.json"), data)
+}
+
+// htmlPath returns the path of the page of importPath relative to the output
+// directory; the first path element is the vanity domain itself.
+func htmlPath(importPath string) string {
+	_, rel, _ := strings.Cut(importPath, "/")
+	return path.Join(rel, "index.html")
+}
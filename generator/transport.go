@@ -0,0 +1,77 @@
+package generator
+
+import (
+	"log"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// loggingTransport logs every API request at debug level so slow endpoints
+// can be spotted without a profiler.
+type loggingTransport struct {
+	base http.RoundTripper
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	attrs := []any{
+		slog.String("method", req.Method),
+		slog.String("endpoint", req.URL.Path),
+		slog.Int64("duration_ms", time.Since(start).Milliseconds()),
+	}
+	if resp != nil {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	}
+	slog.DebugContext(req.Context(), "api.call", attrs...)
+	return resp, err
+}
+
+// retryTransport retries requests failing with a network error or a 5xx
+// status, waiting backoff before the first retry and doubling it after
+// each one.
+type retryTransport struct {
+	base    http.RoundTripper
+	retries int
+	backoff time.Duration
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	delay := t.backoff
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if err == nil && resp.StatusCode < 500 || attempt == t.retries || !rewindable(req) {
+			return resp, err
+		}
+		if err != nil {
+			log.Printf("  %s %s failed, retrying in %s: %v", req.Method, req.URL.Path, delay, err)
+		} else {
+			log.Printf("  %s %s returned %s, retrying in %s", req.Method, req.URL.Path, resp.Status, delay)
+			resp.Body.Close()
+		}
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if req.Body != nil && req.Body != http.NoBody {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// rewindable reports whether req can be sent again, i.e. it has no body or
+// the body can be recreated.
+func rewindable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
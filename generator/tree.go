@@ -0,0 +1,189 @@
+package generator
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// packageDirs returns the directories of the root module that contain
+// non-test Go files, skipping nested modules, directories ignored by the go
+// tool and internal packages, which cannot be imported from other modules.
+func packageDirs(tree []TreeEntry, moduleDirs map[string]bool) []string {
+	var dirs []string
+	seen := make(map[string]bool)
+	for _, entry := range tree {
+		if entry.Type != "file" || !strings.HasSuffix(entry.Path, ".go") || strings.HasSuffix(entry.Path, "_test.go") {
+			continue
+		}
+		dir := path.Dir(entry.Path)
+		if dir == "." || seen[dir] || ignoredDir(dir, moduleDirs) || slices.Contains(strings.Split(dir, "/"), "internal") {
+			continue
+		}
+		seen[dir] = true
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
+
+// binaryName returns the name of the first command of the root module, a
+// cmd/<name> directory containing main.go, or "" if there is none.
+func binaryName(tree []TreeEntry, moduleDirs map[string]bool) string {
+	var names []string
+	for _, entry := range tree {
+		dir, file := path.Split(entry.Path)
+		dir = strings.TrimSuffix(dir, "/")
+		if entry.Type != "file" || file != "main.go" || path.Dir(dir) != "cmd" || ignoredDir(dir, moduleDirs) {
+			continue
+		}
+		names = append(names, path.Base(dir))
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+	return names[0]
+}
+
+func hasReadme(tree []TreeEntry) bool {
+	for _, entry := range tree {
+		if entry.Type == "file" && strings.HasPrefix(strings.ToUpper(entry.Path), "README") && !strings.Contains(entry.Path, "/") {
+			return true
+		}
+	}
+	return false
+}
+
+// hasDocker reports whether the repository root contains a Dockerfile or a
+// Compose file.
+func hasDocker(files map[string]bool) bool {
+	return files["Dockerfile"] || files["docker-compose.yml"]
+}
+
+// hasProto reports whether the repository defines Protocol Buffer schemas,
+// ignoring vendored and test data directories.
+func hasProto(tree []TreeEntry) bool {
+	for _, entry := range tree {
+		if entry.Type == "file" && path.Ext(entry.Path) == ".proto" && !ignoredDir(path.Dir(entry.Path), nil) {
+			return true
+		}
+	}
+	return false
+}
+
+// changelogFiles are the root files recognized as a changelog.
+var changelogFiles = map[string]bool{
+	"CHANGELOG.md": true,
+	"CHANGELOG":    true,
+	"HISTORY.md":   true,
+	"CHANGES.md":   true,
+}
+
+// hasChangelog reports whether the repository root contains a changelog.
+func hasChangelog(files map[string]bool) bool {
+	for name := range changelogFiles {
+		if files[name] {
+			return true
+		}
+	}
+	return false
+}
+
+func ignoredDir(dir string, moduleDirs map[string]bool) bool {
+	for d := dir; d != "."; d = path.Dir(d) {
+		name := path.Base(d)
+		if moduleDirs[d] || name == "testdata" || name == "vendor" ||
+			strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+			return true
+		}
+	}
+	return false
+}
+
+// preferredWorkflows are the workflow file names most likely to hold the
+// main CI pipeline, in order of preference.
+var preferredWorkflows = []string{"ci.yml", "ci.yaml", "go.yml", "go.yaml", "test.yml", "test.yaml", "build.yml", "build.yaml"}
+
+// mainWorkflow returns the file name of the repository's main GitHub Actions
+// workflow, or "" if it has none.
+func mainWorkflow(tree []TreeEntry) string {
+	var workflows []string
+	for _, entry := range tree {
+		if entry.Type != "file" || path.Dir(entry.Path) != ".github/workflows" {
+			continue
+		}
+		if ext := path.Ext(entry.Path); ext == ".yml" || ext == ".yaml" {
+			workflows = append(workflows, path.Base(entry.Path))
+		}
+	}
+	if len(workflows) == 0 {
+		return ""
+	}
+	for _, name := range preferredWorkflows {
+		for _, workflow := range workflows {
+			if workflow == name {
+				return workflow
+			}
+		}
+	}
+	sort.Strings(workflows)
+	return workflows[0]
+}
+
+// ciBadge returns the page of the CI runs and the status badge of the
+// repository: its main GitHub Actions workflow on GitHub and its pipeline on
+// GitLab. Both are "" when the provider's CI is not configured.
+func ciBadge(tree []TreeEntry, files map[string]bool, repo *Repository) (pageURL, badgeURL string) {
+	switch repo.Provider {
+	case "github":
+		if workflow := mainWorkflow(tree); workflow != "" {
+			return repo.HTMLURL + "/actions", repo.HTMLURL + "/actions/workflows/" + workflow + "/badge.svg"
+		}
+	case "gitlab":
+		if files[".gitlab-ci.yml"] {
+			return repo.HTMLURL + "/-/pipelines", repo.HTMLURL + "/badges/" + repo.Ref + "/pipeline.svg"
+		}
+	}
+	return "", ""
+}
+
+// securityPolicyURL returns the GitHub security policy page, or the policy
+// file itself on other providers, or "" without a SECURITY.md.
+func securityPolicyURL(files map[string]bool, repo *Repository) string {
+	for _, name := range []string{"SECURITY.md", ".github/SECURITY.md", "docs/SECURITY.md"} {
+		if !files[name] {
+			continue
+		}
+		if repo.Provider == "github" {
+			return repo.HTMLURL + "/security/policy"
+		}
+		return repo.webURL("blob", "/"+name)
+	}
+	return ""
+}
+
+// coverageServices maps providers to their names in the Codecov and
+// Coveralls badge URLs.
+var coverageServices = map[string][2]string{
+	"github": {"gh", "github"},
+	"gitlab": {"gl", "gitlab"},
+}
+
+// coverageBadgeURL returns the Codecov or Coveralls badge of the repository,
+// detected from their configuration files, or "" if neither is used.
+func coverageBadgeURL(files map[string]bool, repo *Repository) string {
+	service, ok := coverageServices[repo.Provider]
+	if !ok {
+		return ""
+	}
+	switch {
+	case files[".codecov.yml"] || files["codecov.yml"]:
+		return fmt.Sprintf("https://codecov.io/%s/%s/%s/branch/%s/graph/badge.svg", service[0], repo.Owner, repo.Name, repo.DefaultBranch)
+	case files[".coveralls.yml"]:
+		return fmt.Sprintf("https://coveralls.io/repos/%s/%s/%s/badge.svg?branch=%s", service[1], repo.Owner, repo.Name, url.QueryEscape(repo.DefaultBranch))
+	}
+	return ""
+}
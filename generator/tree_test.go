@@ -0,0 +1,28 @@
+package generator
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPackageDirs(t *testing.T) {
+	var tree []TreeEntry
+	for _, p := range []string{
+		"go.mod",
+		"a.go",
+		"client/client.go",
+		"client/client_test.go",
+		"internal/util/util.go",
+		"client/internal/wire.go",
+		"e2e/e2e_test.go",
+		"testdata/x.go",
+		"sub/go.mod",
+		"sub/sub.go",
+	} {
+		tree = append(tree, TreeEntry{Path: p, Type: "file"})
+	}
+	got := packageDirs(tree, map[string]bool{".": true, "sub": true})
+	if want := []string{"client"}; !slices.Equal(got, want) {
+		t.Errorf("packageDirs = %q, want %q", got, want)
+	}
+}
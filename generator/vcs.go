@@ -0,0 +1,318 @@
+package generator
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/semver"
+	"golang.org/x/oauth2"
+)
+
+// VCSClient is the subset of a hosting provider's API the generator needs.
+type VCSClient interface {
+	// ListRepos returns every repository owned by the given organization or group.
+	ListRepos(ctx context.Context, owner string) ([]*Repository, error)
+	// GetFileContent returns the content of a file at repo.Ref.
+	GetFileContent(ctx context.Context, repo *Repository, path string) (string, error)
+	// GetFileTree returns every file and directory at repo.Ref.
+	GetFileTree(ctx context.Context, repo *Repository) ([]TreeEntry, error)
+}
+
+// Repository is the provider independent view of a hosted repository.
+type Repository struct {
+	Provider      string // "github" or "gitlab"
+	Owner         string
+	Name          string
+	Description   string
+	Language      string
+	HTMLURL       string
+	CloneURL      string
+	ReadmeURL     string // rendered README, if the repository has one
+	DefaultBranch string
+	Ref           string // branch scanned for go.mod and Go files
+	Stars         int
+	Watchers      int  // 0 when the listing does not report watchers
+	Discussions   bool // a discussion forum is enabled
+	Forks         int
+	OpenIssues    int
+	UpdatedAt     time.Time
+	PushedAt      time.Time
+	License       string // SPDX identifier
+	Size          int    // repository size in KB, 0 when unknown
+	Topics        []string
+	Homepage      string
+}
+
+// isInternal reports whether a repository is designated internal by the
+// "internal-" name prefix or the "internal" topic.
+func isInternal(repo *Repository) bool {
+	return strings.HasPrefix(repo.Name, "internal-") || slices.Contains(repo.Topics, "internal")
+}
+
+// webURL returns the web page of p, starting with a slash, at repo.Ref. kind is
+// "tree" for directories and "blob" for files.
+func (r *Repository) webURL(kind, p string) string {
+	if r.Provider == "gitlab" {
+		return r.HTMLURL + "/-/" + kind + "/" + r.Ref + p
+	}
+	return r.HTMLURL + "/" + kind + "/" + r.Ref + p
+}
+
+// TreeEntry is a single file or directory of a repository tree.
+type TreeEntry struct {
+	Path string // slash separated, relative to the repository root
+	Type string // "file" or "dir"
+}
+
+func newVCSClient(ctx context.Context, cfg *Config) (VCSClient, error) {
+	transport := http.DefaultTransport
+	if cfg.TLSSkipVerify {
+		log.Printf("WARNING: TLS certificate verification is disabled; API responses can be intercepted or forged")
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+		transport = t
+	}
+	if cfg.HTTPRetries > 0 {
+		transport = &retryTransport{base: transport, retries: cfg.HTTPRetries, backoff: cfg.HTTPRetryBackoff}
+	}
+	switch cfg.VCSProvider {
+	case "github":
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: transport})
+		var ts oauth2.TokenSource
+		if cfg.GitHubAppID != 0 {
+			var err error
+			ts, err = newAppTokenSource(cfg, &http.Client{Transport: &loggingTransport{base: transport}, Timeout: 30 * time.Second})
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			token := githubToken(cfg.Keychain)
+			if token == "" {
+				return nil, fmt.Errorf("GITHUB_TOKEN environment variable is required")
+			}
+			ts = oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
+		}
+		client, err := newGitHubClient(ctx, ts, cfg.APIBaseURL, cfg.GitHubGraphQL)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.GitHubPerPage > 0 {
+			client.perPage = cfg.GitHubPerPage
+		}
+		return client, nil
+	case "gitlab":
+		return newGitLabClient(os.Getenv("GITLAB_TOKEN"), cfg.APIBaseURL, transport), nil
+	case "bitbucket":
+		return nil, fmt.Errorf("vcs provider %q is not supported yet", cfg.VCSProvider)
+	default:
+		return nil, fmt.Errorf("unknown vcs provider %q", cfg.VCSProvider)
+	}
+}
+
+// userRepoLister is implemented by clients that can list the repositories
+// owned by a user account rather than an organization.
+type userRepoLister interface {
+	ListUserRepos(ctx context.Context, user string) ([]*Repository, error)
+}
+
+// contributorCounter is implemented by clients that can count the
+// contributors of a repository.
+type contributorCounter interface {
+	CountContributors(ctx context.Context, repo *Repository) (int, error)
+}
+
+// commitCounter is implemented by clients that can count the commits made to
+// repo.Ref since a point in time.
+type commitCounter interface {
+	CountCommitsSince(ctx context.Context, repo *Repository, since time.Time) (int, error)
+}
+
+// commitsPerDay returns the average number of daily commits over the last
+// days days.
+func commitsPerDay(ctx context.Context, client VCSClient, repo *Repository, days int) float64 {
+	counter, ok := client.(commitCounter)
+	if !ok {
+		return 0
+	}
+	count, err := counter.CountCommitsSince(ctx, repo, time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		log.Printf("  Failed to count commits for %s: %v", repo.Name, err)
+		return 0
+	}
+	return float64(count) / float64(days)
+}
+
+// detailsFetcher is implemented by clients whose repository listing leaves
+// out Watchers and Discussions. FetchDetails fills both in with one request.
+type detailsFetcher interface {
+	FetchDetails(ctx context.Context, repo *Repository) error
+}
+
+func fetchDetails(ctx context.Context, client VCSClient, repo *Repository) {
+	fetcher, ok := client.(detailsFetcher)
+	if !ok {
+		return
+	}
+	if err := fetcher.FetchDetails(ctx, repo); err != nil {
+		log.Printf("  Failed to fetch details for %s: %v", repo.Name, err)
+	}
+}
+
+func countContributors(ctx context.Context, client VCSClient, repo *Repository) int {
+	counter, ok := client.(contributorCounter)
+	if !ok {
+		return 0
+	}
+	count, err := counter.CountContributors(ctx, repo)
+	if err != nil {
+		log.Printf("  Failed to count contributors for %s: %v", repo.Name, err)
+		return 0
+	}
+	return count
+}
+
+// Release is a published release of a repository.
+type Release struct {
+	Tag         string
+	PublishedAt time.Time
+	Body        string
+	Assets      []ReleaseAsset
+}
+
+// ReleaseAsset is a file attached to a release.
+type ReleaseAsset struct {
+	Name          string
+	DownloadURL   string
+	DownloadCount int
+}
+
+// releaseLister is implemented by clients that can list the releases of a
+// repository, newest first.
+type releaseLister interface {
+	ListReleases(ctx context.Context, repo *Repository) ([]Release, error)
+}
+
+// listReleases returns the releases of repo, and false when the client cannot
+// list them.
+func listReleases(ctx context.Context, client VCSClient, repo *Repository) ([]Release, bool) {
+	lister, ok := client.(releaseLister)
+	if !ok {
+		return nil, false
+	}
+	releases, err := lister.ListReleases(ctx, repo)
+	if err != nil {
+		log.Printf("  Failed to list releases for %s: %v", repo.Name, err)
+		return nil, false
+	}
+	return releases, true
+}
+
+// archiveDownloader is implemented by clients that can download repo.Ref as
+// a gzipped tarball in a single request.
+type archiveDownloader interface {
+	DownloadArchive(ctx context.Context, repo *Repository) (io.ReadCloser, error)
+}
+
+// RateLimit is the API quota of the authenticated client.
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// rateLimiter is implemented by clients that can report their API quota.
+type rateLimiter interface {
+	RateLimit(ctx context.Context) (RateLimit, error)
+}
+
+func rateLimit(ctx context.Context, client VCSClient) (RateLimit, bool) {
+	limiter, ok := client.(rateLimiter)
+	if !ok {
+		return RateLimit{}, false
+	}
+	rate, err := limiter.RateLimit(ctx)
+	if err != nil {
+		log.Printf("  Failed to get rate limit: %v", err)
+		return RateLimit{}, false
+	}
+	return rate, true
+}
+
+// tagLister is implemented by clients that can list the tags of a repository.
+type tagLister interface {
+	ListTags(ctx context.Context, repo *Repository) ([]string, error)
+}
+
+func listTags(ctx context.Context, client VCSClient, repo *Repository) []string {
+	lister, ok := client.(tagLister)
+	if !ok {
+		return nil
+	}
+	tags, err := lister.ListTags(ctx, repo)
+	if err != nil {
+		log.Printf("  Failed to list tags for %s: %v", repo.Name, err)
+		return nil
+	}
+	return tags
+}
+
+// latestTag returns the highest semantic version among the tags starting
+// with prefix, or "" if none of them is a valid semantic version. The prefix
+// is stripped and a missing "v" added, so with prefix "release-" the tag
+// release-1.2.3 is returned as v1.2.3.
+func latestTag(tags []string, prefix string) string {
+	latest := ""
+	for _, tag := range tags {
+		version, ok := strings.CutPrefix(tag, prefix)
+		if !ok {
+			continue
+		}
+		if prefix != "" && !strings.HasPrefix(version, "v") {
+			version = "v" + version
+		}
+		if semver.IsValid(version) && (latest == "" || semver.Compare(version, latest) > 0) {
+			latest = version
+		}
+	}
+	return latest
+}
+
+// majorVersions counts the distinct major versions above v1 among the tags
+// starting with prefix, i.e. the breaking releases of the module.
+func majorVersions(tags []string, prefix string) int {
+	majors := make(map[string]bool)
+	for _, tag := range tags {
+		version, ok := strings.CutPrefix(tag, prefix)
+		if !ok {
+			continue
+		}
+		if prefix != "" && !strings.HasPrefix(version, "v") {
+			version = "v" + version
+		}
+		if major := semver.Major(version); major != "" && major != "v0" && major != "v1" {
+			majors[major] = true
+		}
+	}
+	return len(majors)
+}
+
+// apiStability classifies a semantic version tag: v0 releases make no
+// compatibility promise, v1 and later do.
+func apiStability(tag string) string {
+	switch {
+	case !semver.IsValid(tag):
+		return ""
+	case semver.Major(tag) == "v0":
+		return "Unstable"
+	default:
+		return "Stable"
+	}
+}
@@ -0,0 +1,80 @@
+package generator
+
+import (
+	"context"
+	"log"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/semver"
+	"gopkg.in/yaml.v3"
+)
+
+var goVersionRe = regexp.MustCompile(`^\d+\.\d+(\.\d+|\.x)?$`)
+
+// goVersionKeys are the workflow keys holding Go versions: the setup-go input
+// and the usual names of matrix dimensions.
+var goVersionKeys = map[string]bool{
+	"go-version":  true,
+	"go-versions": true,
+	"go":          true,
+}
+
+// testedGoVersions returns the Go versions the repository's GitHub Actions
+// workflows run against, sorted from oldest to newest.
+func testedGoVersions(ctx context.Context, client VCSClient, repo *Repository, tree []TreeEntry) []string {
+	found := make(map[string]bool)
+	for _, entry := range tree {
+		if entry.Type != "file" || path.Dir(entry.Path) != ".github/workflows" {
+			continue
+		}
+		if ext := path.Ext(entry.Path); ext != ".yml" && ext != ".yaml" {
+			continue
+		}
+		content, err := client.GetFileContent(ctx, repo, entry.Path)
+		if err != nil {
+			log.Printf("  Failed to read %s: %v", entry.Path, err)
+			continue
+		}
+		var doc yaml.Node
+		if err := yaml.Unmarshal([]byte(content), &doc); err != nil {
+			log.Printf("  Failed to parse %s: %v", entry.Path, err)
+			continue
+		}
+		collectGoVersions(&doc, false, found)
+	}
+
+	versions := make([]string, 0, len(found))
+	for v := range found {
+		versions = append(versions, v)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return semver.Compare(goSemver(versions[i]), goSemver(versions[j])) < 0
+	})
+	return versions
+}
+
+// collectGoVersions walks a YAML document and records the version-like
+// scalars found below one of goVersionKeys.
+func collectGoVersions(node *yaml.Node, underKey bool, found map[string]bool) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if underKey && goVersionRe.MatchString(node.Value) {
+			found[node.Value] = true
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			collectGoVersions(node.Content[i+1], goVersionKeys[node.Content[i].Value], found)
+		}
+	default:
+		for _, child := range node.Content {
+			collectGoVersions(child, underKey, found)
+		}
+	}
+}
+
+func goSemver(version string) string {
+	return "v" + strings.TrimSuffix(version, ".x")
+}
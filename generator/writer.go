@@ -0,0 +1,98 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// templateExecutor is satisfied by both text/template and html/template.
+type templateExecutor interface {
+	Execute(w io.Writer, data any) error
+}
+
+// htmlWriter renders templates into files below outputDir, optionally
+// minifying the output.
+type htmlWriter struct {
+	outputDir  string
+	baseDomain string
+	minify     bool
+	extraCSS   string // appended to the <head> of every page
+	ogImages   bool   // write og-image.svg next to every package page
+	noRefresh  bool   // redirect package pages with JavaScript instead of a meta refresh
+
+	mu            sync.Mutex // guards the counters
+	pages         int        // number of pages written
+	renderedBytes int        // size of the rendered templates
+	writtenBytes  int        // size of the files written to disk
+}
+
+func (w *htmlWriter) writeTemplate(name string, tmpl templateExecutor, data any) error {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("failed to execute template: %v", err)
+	}
+	out := buf.Bytes()
+	if w.extraCSS != "" {
+		out = bytes.Replace(out, []byte("</head>"), []byte("<style>\n"+w.extraCSS+"\n</style>\n</head>"), 1)
+	}
+	if w.minify {
+		out = minifyHTML(out)
+	}
+
+	if err := writeFileAtomic(name, out); err != nil {
+		return err
+	}
+	w.mu.Lock()
+	w.pages++
+	w.renderedBytes += buf.Len()
+	w.writtenBytes += len(out)
+	w.mu.Unlock()
+	return nil
+}
+
+// writeFileAtomic writes data to a temporary file next to name and renames it
+// into place, so a crash never leaves a partially written file behind.
+func writeFileAtomic(name string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(name), "."+filepath.Base(name)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create file: %v", err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write file: %v", err)
+	}
+	if err := f.Chmod(0644); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to set file mode: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write file: %v", err)
+	}
+	if err := os.Rename(f.Name(), name); err != nil {
+		return fmt.Errorf("failed to rename file: %v", err)
+	}
+	return nil
+}
+
+// minifyHTML strips the indentation and blank lines inherited from the
+// templates. Line breaks are kept so inline scripts and styles stay valid.
+func minifyHTML(b []byte) []byte {
+	out := make([]byte, 0, len(b))
+	for _, line := range bytes.Split(b, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		if len(out) > 0 {
+			out = append(out, '\n')
+		}
+		out = append(out, line...)
+	}
+	return out
+}